@@ -71,6 +71,9 @@ func (server *MockHTTPServer) validateRequestIfNeeded(endpoint string, r *http.R
 func (server *MockHTTPServer) handleMatchingStub(endpoint string, w http.ResponseWriter) {
 	stub := server.responses[endpoint]
 	if stub.response != "" {
+		if stub.statusCode != 0 {
+			w.WriteHeader(stub.statusCode)
+		}
 		_, err := fmt.Fprintln(w, stub.response)
 		if err != nil {
 			log.Printf("Writing response failed [%s]. Returning HTTP 500 (Internal Server Error)", err)
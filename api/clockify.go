@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+)
+
+const defaultClockifyServerURL = "https://api.clockify.me/api/v1"
+
+// ClockifyAPI is the Clockify API client contract listing all supported calls.
+type ClockifyAPI interface {
+	GetCurrentUser() (*ClockifyUser, error)
+	GetTimeEntries(userID string, start time.Time, end time.Time) ([]ClockifyTimeEntry, error)
+	GetProjectById(id string) (*ClockifyProject, error)
+}
+
+// ClockifyAPIHTTPClient is the implementation of ClockifyAPI using an HTTP client.
+type ClockifyAPIHTTPClient struct {
+	client *http.Client
+}
+
+// NewClockifyAPI creates a new API client for Clockify.
+func NewClockifyAPI() ClockifyAPI {
+	api := &ClockifyAPIHTTPClient{}
+	api.client = &http.Client{}
+	return api
+}
+
+// ClockifyUser contains personal information about the Clockify user.
+type ClockifyUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ClockifyTimeEntry contains details about the entry recorded by the user, like description, time interval and project.
+type ClockifyTimeEntry struct {
+	ID           string               `json:"id"`
+	Description  string               `json:"description"`
+	ProjectID    string               `json:"projectId"`
+	TimeInterval ClockifyTimeInterval `json:"timeInterval"`
+}
+
+// ClockifyTimeInterval is the period of time covered by a time entry. End is nil while the entry is still running.
+type ClockifyTimeInterval struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+// ClockifyProject is a mapping of the project id to the project name.
+type ClockifyProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetCurrentUser retrieves the user profile, using the Clockify API key stored in the configuration file.
+func (clockify *ClockifyAPIHTTPClient) GetCurrentUser() (*ClockifyUser, error) {
+	var user ClockifyUser
+	if err := clockify.getAuthenticated("GetCurrentUser", "/user", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetTimeEntries retrieves all time entries of the user within a given time period, represented by start and end.
+// It uses the Clockify API key and workspace stored in the configuration file.
+func (clockify *ClockifyAPIHTTPClient) GetTimeEntries(userID string, start time.Time, end time.Time) ([]ClockifyTimeEntry, error) {
+	params := map[string]string{
+		"start":     start.UTC().Format(time.RFC3339),
+		"end":       end.UTC().Format(time.RFC3339),
+		"page-size": "1000",
+	}
+
+	var entries []ClockifyTimeEntry
+	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", config.Get(config.ClockifyWorkspaceID), userID)
+	if err := clockify.getAuthenticated("GetTimeEntries", path, params, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetProjectById retrieves the project data using the specified id.
+// It uses the Clockify API key and workspace stored in the configuration file.
+func (clockify *ClockifyAPIHTTPClient) GetProjectById(id string) (*ClockifyProject, error) {
+	var project ClockifyProject
+	path := fmt.Sprintf("/workspaces/%s/projects/%s", config.Get(config.ClockifyWorkspaceID), id)
+	if err := clockify.getAuthenticated("GetProjectById", path, nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (clockify *ClockifyAPIHTTPClient) getAuthenticated(operation string, path string, params map[string]string, target interface{}) error {
+	serverURL := config.Get(config.ClockifyServerURL)
+	if serverURL == "" {
+		serverURL = defaultClockifyServerURL
+	}
+
+	req, err := http.NewRequest("GET", serverURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("[%s] Request failed! Error: %s", operation, err)
+	}
+
+	q := req.URL.Query()
+	for p := range params {
+		q.Add(p, params[p])
+	}
+	req.URL.RawQuery = q.Encode()
+
+	req.Header.Add("X-Api-Key", config.Get(config.ClockifyAPIKey))
+	req.Header.Add("Accept", "application/json")
+	resp, err := clockify.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("[%s] Request failed! Error: %s", operation, err)
+	} else if resp.StatusCode != 200 {
+		return fmt.Errorf("[%s] Request failed with status: %d", operation, resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(target)
+	if err != nil {
+		return fmt.Errorf("[%s] Error unmarshalling response: %s", operation, err)
+	}
+
+	return resp.Body.Close()
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockifyApi_GetCurrentUser(t *testing.T) {
+	expectedUser := ClockifyUser{
+		ID:    "user-1",
+		Name:  "TogglSync Tester",
+		Email: "tester@toggl-sync.com",
+	}
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/user",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, "clockify-key", r.Header.Get("X-Api-Key"))
+			},
+			ResponseCode: http.StatusOK,
+			ResponseBody: AsJSONString(expectedUser),
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.ClockifyServerURL, server.URL)
+	config.Set(config.ClockifyAPIKey, "clockify-key")
+
+	clockifyAPI := NewClockifyAPI()
+	user, err := clockifyAPI.GetCurrentUser()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUser, *user)
+}
+
+func TestClockifyApi_GetCurrentUser_ErrorWhenRequestFails(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/user",
+			ResponseCode: http.StatusUnauthorized,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.ClockifyServerURL, server.URL)
+
+	clockifyAPI := NewClockifyAPI()
+	_, err := clockifyAPI.GetCurrentUser()
+	assert.NotNilf(t, err, "API errors should be returned to the client")
+}
+
+func TestClockifyApi_GetTimeEntries(t *testing.T) {
+	start, _ := time.Parse(time.RFC3339, "2020-05-22T09:00:00Z")
+	end, _ := time.Parse(time.RFC3339, "2020-05-22T10:00:00Z")
+	expectedEntries := []ClockifyTimeEntry{
+		{
+			ID:           "entry-1",
+			Description:  "ENG-1001",
+			TimeInterval: ClockifyTimeInterval{Start: start, End: &end},
+		},
+		{
+			ID:           "entry-2",
+			Description:  "Team catch-up",
+			ProjectID:    "project-1",
+			TimeInterval: ClockifyTimeInterval{Start: end},
+		},
+	}
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/workspaces/workspace-1/user/user-1/time-entries",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, "2020-05-22T00:00:00Z", r.URL.Query().Get("start"))
+				assert.Equal(t, "2020-05-23T00:00:00Z", r.URL.Query().Get("end"))
+			},
+			ResponseCode: http.StatusOK,
+			ResponseBody: AsJSONString(expectedEntries),
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.ClockifyServerURL, server.URL)
+	config.Set(config.ClockifyWorkspaceID, "workspace-1")
+
+	clockifyAPI := NewClockifyAPI()
+	startDate, _ := time.Parse("2006-01-02", "2020-05-22")
+	entries, err := clockifyAPI.GetTimeEntries("user-1", startDate, startDate.AddDate(0, 0, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, expectedEntries, entries)
+}
+
+func TestClockifyApi_GetTimeEntries_ErrorWhenResponseHasUnexpectedFormat(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/time-entries",
+			ResponseCode: http.StatusOK,
+			ResponseBody: AsJSONString("Bogus!"),
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.ClockifyServerURL, server.URL)
+
+	clockifyAPI := NewClockifyAPI()
+	_, err := clockifyAPI.GetTimeEntries("user-1", time.Now(), time.Now())
+	assert.NotNilf(t, err, "JSON marshalling errors should be returned to the client")
+}
+
+func TestClockifyApi_GetProjectById(t *testing.T) {
+	expectedProject := ClockifyProject{
+		ID:   "project-1",
+		Name: "Meetings",
+	}
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/workspaces/workspace-1/projects/project-1",
+			ResponseCode: http.StatusOK,
+			ResponseBody: AsJSONString(expectedProject),
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.ClockifyServerURL, server.URL)
+	config.Set(config.ClockifyWorkspaceID, "workspace-1")
+
+	clockifyAPI := NewClockifyAPI()
+	project, err := clockifyAPI.GetProjectById("project-1")
+	assert.Nil(t, err)
+	assert.Equal(t, expectedProject, *project)
+}
+
+func TestClockifyApi_GetProjectById_ErrorWhenRequestErrors(t *testing.T) {
+	config.Set(config.ClockifyServerURL, "%#2")
+
+	clockifyAPI := NewClockifyAPI()
+	_, err := clockifyAPI.GetProjectById("project-1")
+	assert.NotNil(t, err, "Request errors (e.g. misconfiguration) should be returned to the client")
+}
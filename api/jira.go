@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/javicg/toggl-sync/config"
@@ -15,78 +17,368 @@ const workLogEntryCommentFooter = "Added automatically by toggl-sync"
 
 // JiraAPI is the Jira API client contract listing all supported calls.
 type JiraAPI interface {
-	LogWork(ticket string, timeSpent time.Duration) error
-	LogWorkWithUserDescription(ticket string, timeSpent time.Duration, description string) error
+	LogWork(ticket string, started time.Time, timeSpent time.Duration) error
+	LogWorkWithUserDescription(ticket string, started time.Time, timeSpent time.Duration, description string) error
+	AddWorklog(ticket string, started time.Time, timeSpent time.Duration, description string, options WorklogOptions) (id string, err error)
+	UpdateWorklog(ticket string, id string, timeSpent time.Duration, description string, options WorklogOptions) error
+	DeleteWorklog(ticket string, id string) error
+	GetMyself() (*JiraUser, error)
+	GetWorklogs(day time.Time) ([]JiraWorklog, error)
+	ProjectExists(key string) (bool, error)
+	TicketExists(ticket string) (bool, error)
+}
+
+// WorklogOptions contains the optional settings of the worklogs added (or updated) on Jira.
+type WorklogOptions struct {
+	Visibility *WorklogVisibility
+}
+
+// Available worklog visibility types
+const (
+	VisibilityGroup string = "group"
+	VisibilityRole  string = "role"
+)
+
+// WorklogVisibility restricts who can see a worklog to the members of a Jira group or project role (see VisibilityGroup and VisibilityRole).
+type WorklogVisibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// JiraUser contains the details of a Jira user.
+// Jira Cloud identifies users by AccountID, while Jira Server uses Name.
+type JiraUser struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+func (user JiraUser) sameAs(other JiraUser) bool {
+	if user.AccountID != "" {
+		return user.AccountID == other.AccountID
+	}
+	return user.Name == other.Name
+}
+
+// JiraWorklog contains the details of a worklog logged on a Jira ticket.
+type JiraWorklog struct {
+	ID               string
+	Ticket           string
+	Started          time.Time
+	TimeSpentSeconds int
+	Comment          string
 }
 
 // JiraAPIHTTPClient is the implementation of JiraAPI using an HTTP client.
 type JiraAPIHTTPClient struct {
-	client *http.Client
+	client   *http.Client
+	instance string
 }
 
-// NewJiraAPI creates a new API client for Jira.
+// NewJiraAPI creates a new API client for the default Jira instance.
 func NewJiraAPI() JiraAPI {
+	return NewJiraAPIForInstance("")
+}
+
+// NewJiraAPIForInstance creates a new API client for the named Jira instance (see config.GetJiraInstances).
+func NewJiraAPIForInstance(instance string) JiraAPI {
 	api := &JiraAPIHTTPClient{}
 	api.client = &http.Client{}
+	api.instance = instance
 	return api
 }
 
 type workLogEntry struct {
-	Comment          string `json:"comment"`
-	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	Comment          string             `json:"comment"`
+	Started          string             `json:"started,omitempty"`
+	TimeSpentSeconds int                `json:"timeSpentSeconds"`
+	Visibility       *WorklogVisibility `json:"visibility,omitempty"`
+}
+
+// withOptions applies the optional settings of the worklog (e.g. its visibility)
+func (entry *workLogEntry) withOptions(options WorklogOptions) *workLogEntry {
+	entry.Visibility = options.Visibility
+	return entry
+}
+
+// startedAt sets when the work started, keeping the time zone of the time provided (Jira uses the current time otherwise)
+func (entry *workLogEntry) startedAt(started time.Time) *workLogEntry {
+	if !started.IsZero() {
+		entry.Started = started.Format(jiraTimestampLayout)
+	}
+	return entry
 }
 
-// LogWork logs the work on the specified Jira ticket, using the provided duration and a default description
-func (jira *JiraAPIHTTPClient) LogWork(ticket string, timeSpent time.Duration) (err error) {
-	entry := createWorkLogEntry(timeSpent)
+// LogWork logs the work on the specified Jira ticket, using the provided start, duration and a default description
+func (jira *JiraAPIHTTPClient) LogWork(ticket string, started time.Time, timeSpent time.Duration) (err error) {
+	entry := createWorkLogEntry(timeSpent).startedAt(started)
 	return jira.logEntry(ticket, entry)
 }
 
 func createWorkLogEntry(timeSpent time.Duration) *workLogEntry {
 	return &workLogEntry{
-		Comment:          workLogEntryCommentFooter,
+		Comment:          withFooter(""),
 		TimeSpentSeconds: int(timeSpent.Seconds()),
 	}
 }
 
-// LogWorkWithUserDescription logs the work on the specified Jira ticket, using the provided duration and a description generated by the user
-func (jira *JiraAPIHTTPClient) LogWorkWithUserDescription(ticket string, timeSpent time.Duration, description string) (err error) {
-	entry := createWorkLogEntryWithUserDescription(timeSpent, description)
+// LogWorkWithUserDescription logs the work on the specified Jira ticket, using the provided start, duration and a description generated by the user
+func (jira *JiraAPIHTTPClient) LogWorkWithUserDescription(ticket string, started time.Time, timeSpent time.Duration, description string) (err error) {
+	entry := createWorkLogEntryWithUserDescription(timeSpent, description).startedAt(started)
 	return jira.logEntry(ticket, entry)
 }
 
 func createWorkLogEntryWithUserDescription(timeSpent time.Duration, description string) *workLogEntry {
 	return &workLogEntry{
-		Comment:          fmt.Sprintf("%s\n%s", description, workLogEntryCommentFooter),
+		Comment:          withFooter(description),
 		TimeSpentSeconds: int(timeSpent.Seconds()),
 	}
 }
 
+// withFooter appends the footer to the worklog comment: the one in config.SyncCommentFooter (or a default one),
+// unless disabled with config.SyncCommentFooterEnabled
+func withFooter(comment string) string {
+	footer := workLogEntryCommentFooter
+	if config.IsSet(config.SyncCommentFooter) {
+		footer = config.Get(config.SyncCommentFooter)
+	}
+	if config.IsSet(config.SyncCommentFooterEnabled) && !config.GetBool(config.SyncCommentFooterEnabled) {
+		footer = ""
+	}
+
+	if comment == "" || footer == "" {
+		return comment + footer
+	}
+	return fmt.Sprintf("%s\n%s", comment, footer)
+}
+
 func (jira *JiraAPIHTTPClient) logEntry(ticket string, entry *workLogEntry) error {
+	_, err := jira.addEntry(ticket, entry)
+	return err
+}
+
+// AddWorklog logs the work on the specified Jira ticket (started at the time provided, if any) and returns the id of the new worklog.
+// An empty description stands for project work (i.e. the default description is used).
+func (jira *JiraAPIHTTPClient) AddWorklog(ticket string, started time.Time, timeSpent time.Duration, description string, options WorklogOptions) (id string, err error) {
+	return jira.addEntry(ticket, createWorkLogEntryFor(timeSpent, description).startedAt(started).withOptions(options))
+}
+
+func (jira *JiraAPIHTTPClient) addEntry(ticket string, entry *workLogEntry) (string, error) {
 	entryJSON, err := json.Marshal(entry)
 	if err != nil {
-		return fmt.Errorf("[LogWork] Marshalling of work entry failed! Error: %s", err)
+		return "", fmt.Errorf("[LogWork] Marshalling of work entry failed! Error: %s", err)
 	}
 
-	resp, err := jira.postAuthenticated("/issue/"+ticket+"/worklog", bytes.NewBuffer(entryJSON))
+	resp, err := jira.requestAuthenticated("POST", "/issue/"+ticket+"/worklog", bytes.NewBuffer(entryJSON))
 	if err != nil {
-		return err
+		return "", err
 	} else if resp.StatusCode != 201 {
-		return fmt.Errorf("[LogWork] Request to log work for ticket [%s] failed with status [%d]", ticket, resp.StatusCode)
+		return "", fmt.Errorf("[LogWork] Request to log work for ticket [%s] failed with status [%d]", ticket, resp.StatusCode)
+	}
+
+	var created createdWorkLog
+	if err = json.NewDecoder(resp.Body).Decode(&created); err != nil && err != io.EOF {
+		return "", fmt.Errorf("[LogWork] Error unmarshalling response: %s", err)
+	}
+
+	return created.ID, resp.Body.Close()
+}
+
+type createdWorkLog struct {
+	ID string `json:"id"`
+}
+
+// UpdateWorklog replaces the duration, description and options of an existing worklog on the specified Jira ticket
+func (jira *JiraAPIHTTPClient) UpdateWorklog(ticket string, id string, timeSpent time.Duration, description string, options WorklogOptions) error {
+	entryJSON, err := json.Marshal(createWorkLogEntryFor(timeSpent, description).withOptions(options))
+	if err != nil {
+		return fmt.Errorf("[UpdateWorklog] Marshalling of work entry failed! Error: %s", err)
+	}
+
+	resp, err := jira.requestAuthenticated("PUT", "/issue/"+ticket+"/worklog/"+id, bytes.NewBuffer(entryJSON))
+	if err != nil {
+		return err
+	} else if resp.StatusCode != 200 {
+		return fmt.Errorf("[UpdateWorklog] Request to update worklog [%s] for ticket [%s] failed with status [%d]", id, ticket, resp.StatusCode)
+	}
+
+	return resp.Body.Close()
+}
+
+// DeleteWorklog removes an existing worklog from the specified Jira ticket
+func (jira *JiraAPIHTTPClient) DeleteWorklog(ticket string, id string) error {
+	resp, err := jira.requestAuthenticated("DELETE", "/issue/"+ticket+"/worklog/"+id, nil)
+	if err != nil {
+		return err
+	} else if resp.StatusCode != 204 {
+		return fmt.Errorf("[DeleteWorklog] Request to delete worklog [%s] for ticket [%s] failed with status [%d]", id, ticket, resp.StatusCode)
 	}
 
 	return resp.Body.Close()
 }
 
-func (jira *JiraAPIHTTPClient) postAuthenticated(path string, body io.Reader) (resp *http.Response, err error) {
-	req, err := http.NewRequest("POST", config.Get(config.JiraServerURL)+"/rest/api/latest"+path, body)
+// GetMyself retrieves the details of the Jira user whose credentials are stored in the configuration file
+func (jira *JiraAPIHTTPClient) GetMyself() (*JiraUser, error) {
+	var user JiraUser
+	if err := jira.getAuthenticated("GetMyself", "/myself", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+type jiraSearchResults struct {
+	StartAt    int `json:"startAt"`
+	MaxResults int `json:"maxResults"`
+	Total      int `json:"total"`
+	Issues     []struct {
+		Key string `json:"key"`
+	} `json:"issues"`
+}
+
+type jiraIssueWorklogs struct {
+	Worklogs []struct {
+		ID               string   `json:"id"`
+		Author           JiraUser `json:"author"`
+		Started          string   `json:"started"`
+		TimeSpentSeconds int      `json:"timeSpentSeconds"`
+		Comment          string   `json:"comment"`
+	} `json:"worklogs"`
+}
+
+const jiraTimestampLayout = "2006-01-02T15:04:05.000-0700"
+
+// GetWorklogs retrieves all worklogs logged by the current user (see GetMyself) on the given day.
+// The day starts at midnight in the location of the time provided.
+func (jira *JiraAPIHTTPClient) GetWorklogs(day time.Time) ([]JiraWorklog, error) {
+	me, err := jira.GetMyself()
+	if err != nil {
+		return nil, err
+	}
+
+	date := day.Format("2006-01-02")
+	tickets, err := jira.searchTickets(fmt.Sprintf("worklogAuthor = currentUser() AND worklogDate = \"%s\"", date))
+	if err != nil {
+		return nil, err
+	}
+
+	var worklogs []JiraWorklog
+	for _, ticket := range tickets {
+		var issueWorklogs jiraIssueWorklogs
+		if err = jira.getAuthenticated("GetWorklogs", "/issue/"+ticket+"/worklog", nil, &issueWorklogs); err != nil {
+			return nil, err
+		}
+
+		for _, worklog := range issueWorklogs.Worklogs {
+			started, err := time.Parse(jiraTimestampLayout, worklog.Started)
+			if err != nil {
+				return nil, fmt.Errorf("[GetWorklogs] Error parsing worklog start [%s]: %s", worklog.Started, err)
+			}
+			if !me.sameAs(worklog.Author) || started.In(day.Location()).Format("2006-01-02") != date {
+				continue
+			}
+			worklogs = append(worklogs, JiraWorklog{
+				ID:               worklog.ID,
+				Ticket:           ticket,
+				Started:          started,
+				TimeSpentSeconds: worklog.TimeSpentSeconds,
+				Comment:          worklog.Comment,
+			})
+		}
+	}
+	return worklogs, nil
+}
+
+func (jira *JiraAPIHTTPClient) searchTickets(jql string) ([]string, error) {
+	var tickets []string
+	for {
+		params := url.Values{}
+		params.Add("jql", jql)
+		params.Add("fields", "key")
+		params.Add("startAt", strconv.Itoa(len(tickets)))
+
+		var results jiraSearchResults
+		if err := jira.getAuthenticated("GetWorklogs", "/search", params, &results); err != nil {
+			return nil, err
+		}
+		for _, issue := range results.Issues {
+			tickets = append(tickets, issue.Key)
+		}
+		if len(results.Issues) == 0 || len(tickets) >= results.Total {
+			return tickets, nil
+		}
+	}
+}
+
+// ProjectExists checks whether the project exists (and is visible to the user whose credentials are stored in the configuration file)
+func (jira *JiraAPIHTTPClient) ProjectExists(key string) (bool, error) {
+	return jira.exists("ProjectExists", "/project/"+key)
+}
+
+// TicketExists checks whether the ticket exists (and is visible to the user whose credentials are stored in the configuration file)
+func (jira *JiraAPIHTTPClient) TicketExists(ticket string) (bool, error) {
+	return jira.exists("TicketExists", "/issue/"+ticket+"?fields=key")
+}
+
+func (jira *JiraAPIHTTPClient) exists(operation string, path string) (bool, error) {
+	resp, err := jira.requestAuthenticated("GET", path, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		return true, nil
+	case 404:
+		return false, nil
+	default:
+		return false, fmt.Errorf("[%s] Request failed with status: %d", operation, resp.StatusCode)
+	}
+}
+
+func (jira *JiraAPIHTTPClient) getAuthenticated(operation string, path string, params url.Values, target interface{}) error {
+	if len(params) != 0 {
+		path = path + "?" + params.Encode()
+	}
+	resp, err := jira.requestAuthenticated("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("[%s] Request failed with status: %d", operation, resp.StatusCode)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("[%s] Error unmarshalling response: %s", operation, err)
+	}
+	return nil
+}
+
+func createWorkLogEntryFor(timeSpent time.Duration, description string) *workLogEntry {
+	if description == "" {
+		return createWorkLogEntry(timeSpent)
+	}
+	return createWorkLogEntryWithUserDescription(timeSpent, description)
+}
+
+func (jira *JiraAPIHTTPClient) requestAuthenticated(method string, path string, body io.Reader) (resp *http.Response, err error) {
+	req, err := http.NewRequest(method, jira.configValue(config.JiraServerURL)+"/rest/api/latest"+path, body)
 	if err != nil {
 		return
 	}
 
-	req.SetBasicAuth(config.Get(config.JiraUsername), config.Get(config.JiraPassword))
+	req.SetBasicAuth(jira.configValue(config.JiraUsername), jira.configValue(config.JiraPassword))
 
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
 	return jira.client.Do(req)
 }
+
+func (jira *JiraAPIHTTPClient) configValue(key string) string {
+	if jira.instance == "" {
+		return config.Get(key)
+	}
+	return config.Get(config.JiraInstanceKey(jira.instance, key))
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+)
+
+// JiraRouter is an implementation of JiraAPI that forwards every call to the Jira instance owning the ticket.
+// Tickets are matched against the project keys of each instance; tickets without a match go to the default instance.
+type JiraRouter struct {
+	defaultAPI JiraAPI
+	factory    func(instance string) JiraAPI
+	instances  map[string]JiraAPI
+	// mutex guards instances, as the router may be shared by concurrent requests (e.g. serve or the web UI)
+	mutex sync.Mutex
+}
+
+// NewJiraRouter creates a new JiraAPI routing calls between the default client and one client per configured instance.
+// Instance clients are created lazily (using the provided factory) the first time a ticket is routed to them.
+func NewJiraRouter(defaultAPI JiraAPI, factory func(instance string) JiraAPI) JiraAPI {
+	return &JiraRouter{
+		defaultAPI: defaultAPI,
+		factory:    factory,
+		instances:  make(map[string]JiraAPI),
+	}
+}
+
+// LogWork logs the work using the client of the Jira instance owning the ticket
+func (router *JiraRouter) LogWork(ticket string, started time.Time, timeSpent time.Duration) error {
+	return router.apiFor(ticket).LogWork(ticket, started, timeSpent)
+}
+
+// LogWorkWithUserDescription logs the work using the client of the Jira instance owning the ticket
+func (router *JiraRouter) LogWorkWithUserDescription(ticket string, started time.Time, timeSpent time.Duration, description string) error {
+	return router.apiFor(ticket).LogWorkWithUserDescription(ticket, started, timeSpent, description)
+}
+
+// AddWorklog logs the work using the client of the Jira instance owning the ticket
+func (router *JiraRouter) AddWorklog(ticket string, started time.Time, timeSpent time.Duration, description string, options WorklogOptions) (string, error) {
+	return router.apiFor(ticket).AddWorklog(ticket, started, timeSpent, description, options)
+}
+
+// UpdateWorklog updates the worklog using the client of the Jira instance owning the ticket
+func (router *JiraRouter) UpdateWorklog(ticket string, id string, timeSpent time.Duration, description string, options WorklogOptions) error {
+	return router.apiFor(ticket).UpdateWorklog(ticket, id, timeSpent, description, options)
+}
+
+// DeleteWorklog deletes the worklog using the client of the Jira instance owning the ticket
+func (router *JiraRouter) DeleteWorklog(ticket string, id string) error {
+	return router.apiFor(ticket).DeleteWorklog(ticket, id)
+}
+
+// GetMyself retrieves the details of the user of the default Jira instance
+func (router *JiraRouter) GetMyself() (*JiraUser, error) {
+	return router.defaultAPI.GetMyself()
+}
+
+// GetWorklogs retrieves the worklogs of the current user on the given day from every Jira instance
+func (router *JiraRouter) GetWorklogs(day time.Time) ([]JiraWorklog, error) {
+	worklogs, err := router.defaultAPI.GetWorklogs(day)
+	if err != nil {
+		return nil, err
+	}
+	for _, instance := range config.GetJiraInstances() {
+		instanceWorklogs, err := router.apiForInstance(instance).GetWorklogs(day)
+		if err != nil {
+			return nil, err
+		}
+		worklogs = append(worklogs, instanceWorklogs...)
+	}
+	return worklogs, nil
+}
+
+// ProjectExists checks the project using the client of the Jira instance owning the project key
+func (router *JiraRouter) ProjectExists(key string) (bool, error) {
+	return router.apiFor(key).ProjectExists(key)
+}
+
+// TicketExists checks the ticket using the client of the Jira instance owning the ticket
+func (router *JiraRouter) TicketExists(ticket string) (bool, error) {
+	return router.apiFor(ticket).TicketExists(ticket)
+}
+
+func (router *JiraRouter) apiFor(ticket string) JiraAPI {
+	return router.apiForInstance(config.GetJiraInstanceForTicket(ticket))
+}
+
+func (router *JiraRouter) apiForInstance(instance string) JiraAPI {
+	if instance == "" {
+		return router.defaultAPI
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+	if _, ok := router.instances[instance]; !ok {
+		router.instances[instance] = router.factory(instance)
+	}
+	return router.instances[instance]
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJiraRouter_LogWork_DefaultInstance(t *testing.T) {
+	ticket := "ENG-1234"
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/issue/" + ticket + "/worklog",
+			ResponseCode: http.StatusCreated,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Reset()
+	config.Set(config.JiraServerURL, server.URL)
+	config.Set(config.JiraProjectKey, []string{"ENG"})
+	config.Set(config.JiraInstanceKey("ops", config.JiraServerURL), "%#2")
+	config.Set(config.JiraInstanceKey("ops", config.JiraProjectKey), []string{"OPS"})
+
+	jiraAPI := NewJiraRouter(NewJiraAPI(), NewJiraAPIForInstance)
+	err := jiraAPI.LogWork(ticket, time.Time{}, time.Duration(60)*time.Second)
+	assert.Nil(t, err)
+}
+
+func TestJiraRouter_LogWork_AdditionalInstance(t *testing.T) {
+	ticket := "OPS-7"
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/issue/" + ticket + "/worklog",
+			RequestValidator: func(r *http.Request) {
+				username, password, _ := r.BasicAuth()
+				assert.Equal(t, "OpsUser", username)
+				assert.Equal(t, "OpsPassword", password)
+			},
+			ResponseCode: http.StatusCreated,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Reset()
+	config.Set(config.JiraServerURL, "%#2")
+	config.Set(config.JiraProjectKey, []string{"ENG"})
+	config.Set(config.JiraInstanceKey("ops", config.JiraServerURL), server.URL)
+	config.Set(config.JiraInstanceKey("ops", config.JiraUsername), "OpsUser")
+	config.Set(config.JiraInstanceKey("ops", config.JiraPassword), "OpsPassword")
+	config.Set(config.JiraInstanceKey("ops", config.JiraProjectKey), []string{"OPS"})
+
+	jiraAPI := NewJiraRouter(NewJiraAPI(), NewJiraAPIForInstance)
+	err := jiraAPI.LogWorkWithUserDescription(ticket, time.Time{}, time.Duration(60)*time.Second, "Support rotation")
+	assert.Nil(t, err)
+}
+
+func TestJiraRouter_InstancesCreatedOnceConcurrently(t *testing.T) {
+	config.Reset()
+	config.Set(config.JiraInstanceKey("ops", config.JiraProjectKey), []string{"OPS"})
+	var created []string
+	factory := func(instance string) JiraAPI {
+		created = append(created, instance)
+		return &JiraAPIHTTPClient{}
+	}
+
+	router := NewJiraRouter(nil, factory).(*JiraRouter)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			router.apiFor("OPS-7")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"ops"}, created)
+	config.Reset()
+}
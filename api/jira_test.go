@@ -30,7 +30,7 @@ func TestJiraApi_LogWork(t *testing.T) {
 	config.Set(config.JiraServerURL, server.URL)
 
 	jiraAPI := NewJiraAPI()
-	err := jiraAPI.LogWork(ticket, time.Duration(60)*time.Second)
+	err := jiraAPI.LogWork(ticket, time.Time{}, time.Duration(60)*time.Second)
 	assert.Nil(t, err)
 }
 
@@ -48,7 +48,7 @@ func TestJiraApi_LogWork_ErrorWhenRequestFails(t *testing.T) {
 	config.Set(config.JiraServerURL, server.URL)
 
 	jiraAPI := NewJiraAPI()
-	err := jiraAPI.LogWork(ticket, time.Duration(60)*time.Second)
+	err := jiraAPI.LogWork(ticket, time.Time{}, time.Duration(60)*time.Second)
 	assert.NotNilf(t, err, "API errors should be returned to the client")
 }
 
@@ -56,7 +56,7 @@ func TestJiraApi_LogWork_ErrorWhenRequestErrors(t *testing.T) {
 	config.Set(config.JiraServerURL, "%#2")
 
 	jiraAPI := NewJiraAPI()
-	err := jiraAPI.LogWork("EXAMPLE-1234", time.Duration(60)*time.Second)
+	err := jiraAPI.LogWork("EXAMPLE-1234", time.Time{}, time.Duration(60)*time.Second)
 	assert.NotNil(t, err, "Request errors (e.g. misconfiguration) should be returned to the client")
 }
 
@@ -64,6 +64,7 @@ func TestJiraApi_LogWorkWithUserDescription(t *testing.T) {
 	ticket := "EXAMPLE-1234"
 	expectedEntry := workLogEntry{
 		Comment:          "Writing toggl-sync tests\nAdded automatically by toggl-sync",
+		Started:          "2020-05-22T08:30:00.000+0200",
 		TimeSpentSeconds: 60,
 	}
 
@@ -78,11 +79,53 @@ func TestJiraApi_LogWorkWithUserDescription(t *testing.T) {
 
 	config.Set(config.JiraServerURL, server.URL)
 
+	// Work starting at 06:30 UTC is logged as started at 08:30 in Madrid (UTC+2 in May)
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	started := time.Date(2020, 5, 22, 6, 30, 0, 0, time.UTC).In(madrid)
 	jiraAPI := NewJiraAPI()
-	err := jiraAPI.LogWorkWithUserDescription(ticket, time.Duration(60)*time.Second, "Writing toggl-sync tests")
+	err := jiraAPI.LogWorkWithUserDescription(ticket, started, time.Duration(60)*time.Second, "Writing toggl-sync tests")
 	assert.Nil(t, err)
 }
 
+func TestJiraApi_LogWork_CustomFooter(t *testing.T) {
+	tests := []struct {
+		name     string
+		footer   string
+		enabled  string
+		expected string
+	}{
+		{"custom footer", "Synced from Toggl", "", "Writing toggl-sync tests\nSynced from Toggl"},
+		{"no footer", "", "false", "Writing toggl-sync tests"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ticket := "EXAMPLE-1234"
+			server := NewHTTPServer().
+				StubAPI(&Stubbing{
+					Endpoint:         "/issue/" + ticket + "/worklog",
+					RequestValidator: validateBodyMatches(t, workLogEntry{Comment: test.expected, TimeSpentSeconds: 60}),
+					ResponseCode:     http.StatusCreated,
+				}).
+				Create()
+			defer server.Close()
+
+			config.Set(config.JiraServerURL, server.URL)
+			if test.footer != "" {
+				config.Set(config.SyncCommentFooter, test.footer)
+				defer config.Unset(config.SyncCommentFooter)
+			}
+			if test.enabled != "" {
+				config.Set(config.SyncCommentFooterEnabled, test.enabled)
+				defer config.Unset(config.SyncCommentFooterEnabled)
+			}
+
+			err := NewJiraAPI().LogWorkWithUserDescription(ticket, time.Time{}, time.Duration(60)*time.Second, "Writing toggl-sync tests")
+			assert.Nil(t, err)
+		})
+	}
+}
+
 func validateBodyMatches(t *testing.T, expectedBody workLogEntry) func(*http.Request) {
 	return func(r *http.Request) {
 		bytes, err := ioutil.ReadAll(r.Body)
@@ -95,9 +138,250 @@ func validateBodyMatches(t *testing.T, expectedBody workLogEntry) func(*http.Req
 				t.Errorf("JSON unmarshalling failed: %s", err)
 			}
 
-			if body != expectedBody {
-				t.Errorf("Unexpected payload: was [%#v] instead of [%#v]", body, expectedBody)
-			}
+			assert.Equal(t, expectedBody, body, "Unexpected payload")
 		}
 	}
 }
+
+func TestJiraApi_AddWorklog(t *testing.T) {
+	ticket := "EXAMPLE-1234"
+	expectedEntry := workLogEntry{
+		Comment:          "Writing toggl-sync tests\nAdded automatically by toggl-sync",
+		TimeSpentSeconds: 60,
+	}
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:         "/issue/" + ticket + "/worklog",
+			RequestValidator: validateBodyMatches(t, expectedEntry),
+			ResponseCode:     http.StatusCreated,
+			ResponseBody:     `{"id": "10042"}`,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	id, err := jiraAPI.AddWorklog(ticket, time.Time{}, time.Duration(60)*time.Second, "Writing toggl-sync tests", WorklogOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "10042", id)
+}
+
+func TestJiraApi_AddWorklog_RestrictedVisibility(t *testing.T) {
+	ticket := "MGMT-1"
+	visibility := &WorklogVisibility{Type: VisibilityGroup, Value: "hr"}
+	expectedEntry := workLogEntry{
+		Comment:          "Interview\nAdded automatically by toggl-sync",
+		TimeSpentSeconds: 60,
+		Visibility:       visibility,
+	}
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:         "/issue/" + ticket + "/worklog",
+			RequestValidator: validateBodyMatches(t, expectedEntry),
+			ResponseCode:     http.StatusCreated,
+			ResponseBody:     `{"id": "10043"}`,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	id, err := NewJiraAPI().AddWorklog(ticket, time.Time{}, time.Duration(60)*time.Second, "Interview", WorklogOptions{Visibility: visibility})
+	assert.Nil(t, err)
+	assert.Equal(t, "10043", id)
+}
+
+func TestJiraApi_UpdateWorklog(t *testing.T) {
+	ticket := "EXAMPLE-1234"
+	expectedEntry := workLogEntry{
+		Comment:          "Added automatically by toggl-sync",
+		TimeSpentSeconds: 120,
+	}
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/issue/" + ticket + "/worklog/10042",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				validateBodyMatches(t, expectedEntry)(r)
+			},
+			ResponseCode: http.StatusOK,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	err := jiraAPI.UpdateWorklog(ticket, "10042", time.Duration(120)*time.Second, "", WorklogOptions{})
+	assert.Nil(t, err)
+}
+
+func TestJiraApi_DeleteWorklog(t *testing.T) {
+	ticket := "EXAMPLE-1234"
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/issue/" + ticket + "/worklog/10042",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+			},
+			ResponseCode: http.StatusNoContent,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	err := jiraAPI.DeleteWorklog(ticket, "10042")
+	assert.Nil(t, err)
+}
+
+func TestJiraApi_DeleteWorklog_ErrorWhenRequestFails(t *testing.T) {
+	ticket := "EXAMPLE-1234"
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/issue/" + ticket + "/worklog/10042",
+			ResponseCode: http.StatusNotFound,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	err := jiraAPI.DeleteWorklog(ticket, "10042")
+	assert.NotNil(t, err)
+}
+
+func TestJiraApi_GetMyself(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/myself",
+			ResponseCode: http.StatusOK,
+			ResponseBody: `{"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "TogglSync Tester", "emailAddress": "tester@toggl-sync.com"}`,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	me, err := jiraAPI.GetMyself()
+	assert.Nil(t, err)
+	assert.Equal(t, &JiraUser{AccountID: "5b10ac8d82e05b22cc7d4ef5", DisplayName: "TogglSync Tester", EmailAddress: "tester@toggl-sync.com"}, me)
+}
+
+func TestJiraApi_GetMyself_ErrorWhenRequestFails(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/myself",
+			ResponseCode: http.StatusUnauthorized,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	_, err := jiraAPI.GetMyself()
+	assert.NotNil(t, err)
+}
+
+func TestJiraApi_GetWorklogs(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/myself",
+			ResponseCode: http.StatusOK,
+			ResponseBody: `{"accountId": "me"}`,
+		}).
+		StubAPI(&Stubbing{
+			Endpoint: "/search",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, `worklogAuthor = currentUser() AND worklogDate = "2020-05-22"`, r.URL.Query().Get("jql"))
+			},
+			ResponseCode: http.StatusOK,
+			ResponseBody: `{"startAt": 0, "maxResults": 50, "total": 1, "issues": [{"key": "ENG-1001"}]}`,
+		}).
+		StubAPI(&Stubbing{
+			Endpoint:     "/issue/ENG-1001/worklog",
+			ResponseCode: http.StatusOK,
+			ResponseBody: `{"worklogs": [
+				{"id": "1", "author": {"accountId": "me"}, "started": "2020-05-22T09:00:00.000+0000", "timeSpentSeconds": 3600, "comment": "Added automatically by toggl-sync"},
+				{"id": "2", "author": {"accountId": "someone-else"}, "started": "2020-05-22T10:00:00.000+0000", "timeSpentSeconds": 600},
+				{"id": "3", "author": {"accountId": "me"}, "started": "2020-05-21T09:00:00.000+0000", "timeSpentSeconds": 900}
+			]}`,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	worklogs, err := jiraAPI.GetWorklogs(time.Date(2020, 5, 22, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	if assert.Len(t, worklogs, 1) {
+		assert.Equal(t, "1", worklogs[0].ID)
+		assert.Equal(t, "ENG-1001", worklogs[0].Ticket)
+		assert.True(t, time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC).Equal(worklogs[0].Started))
+		assert.Equal(t, 3600, worklogs[0].TimeSpentSeconds)
+		assert.Equal(t, "Added automatically by toggl-sync", worklogs[0].Comment)
+	}
+}
+
+func TestJiraApi_ProjectExists(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/project/ENG",
+			ResponseCode: http.StatusOK,
+			ResponseBody: `{"key": "ENG"}`,
+		}).
+		StubAPI(&Stubbing{
+			Endpoint:     "/project/OPS",
+			ResponseCode: http.StatusNotFound,
+		}).
+		StubAPI(&Stubbing{
+			Endpoint:     "/project/ERR",
+			ResponseCode: http.StatusUnauthorized,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	exists, err := jiraAPI.ProjectExists("ENG")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = jiraAPI.ProjectExists("OPS")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+	_, err = jiraAPI.ProjectExists("ERR")
+	assert.NotNil(t, err)
+}
+
+func TestJiraApi_TicketExists(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/issue/MGMT-1?fields=key",
+			ResponseCode: http.StatusOK,
+			ResponseBody: `{"key": "MGMT-1"}`,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.JiraServerURL, server.URL)
+
+	jiraAPI := NewJiraAPI()
+	exists, err := jiraAPI.TicketExists("MGMT-1")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = jiraAPI.TicketExists("MGMT-2")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+)
+
+const (
+	defaultTempoServerURL  = "https://api.tempo.io/core/3"
+	tempoDefaultStartTime  = "09:00:00"
+	tempoAccountAttribute  = "_Account_"
+	tempoWorklogDateFormat = "2006-01-02"
+	tempoWorklogTimeFormat = "15:04:05"
+)
+
+// TempoAPI is the Tempo API client contract listing all supported calls.
+type TempoAPI interface {
+	LogWork(worklog TempoWorklog) error
+}
+
+// TempoAPIHTTPClient is the implementation of TempoAPI using an HTTP client.
+type TempoAPIHTTPClient struct {
+	client *http.Client
+}
+
+// NewTempoAPI creates a new API client for Tempo.
+func NewTempoAPI() TempoAPI {
+	api := &TempoAPIHTTPClient{}
+	api.client = &http.Client{}
+	return api
+}
+
+// TempoWorklog contains the details of the work to be logged on Tempo, including the work attributes required by Tempo.
+// Started is when the work started (in the user's time zone); worklogs start at 09:00 of their Date if it is zero.
+type TempoWorklog struct {
+	Ticket      string
+	TimeSpent   time.Duration
+	Date        string
+	Started     time.Time
+	Description string
+	Account     string
+	Billable    bool
+	Attributes  map[string]string
+}
+
+type tempoWorklogEntry struct {
+	IssueKey         string                  `json:"issueKey"`
+	TimeSpentSeconds int                     `json:"timeSpentSeconds"`
+	BillableSeconds  int                     `json:"billableSeconds"`
+	StartDate        string                  `json:"startDate"`
+	StartTime        string                  `json:"startTime"`
+	Description      string                  `json:"description"`
+	AuthorAccountID  string                  `json:"authorAccountId"`
+	Attributes       []tempoWorkAttributeDTO `json:"attributes,omitempty"`
+}
+
+type tempoWorkAttributeDTO struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// LogWork logs the work on Tempo, using the Tempo credentials stored in the configuration file.
+// The worklog is authored by the Jira account configured in config.TempoAccountID.
+func (tempo *TempoAPIHTTPClient) LogWork(worklog TempoWorklog) error {
+	if _, err := time.Parse(tempoWorklogDateFormat, worklog.Date); err != nil {
+		return fmt.Errorf("[LogWork] Invalid worklog date [%s]: %s", worklog.Date, err)
+	}
+
+	entryJSON, err := json.Marshal(createTempoWorklogEntry(worklog))
+	if err != nil {
+		return fmt.Errorf("[LogWork] Marshalling of work entry failed! Error: %s", err)
+	}
+
+	resp, err := tempo.postAuthenticated("/worklogs", bytes.NewBuffer(entryJSON))
+	if err != nil {
+		return err
+	} else if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return fmt.Errorf("[LogWork] Request to log work on Tempo for ticket [%s] failed with status [%d]", worklog.Ticket, resp.StatusCode)
+	}
+
+	return resp.Body.Close()
+}
+
+func createTempoWorklogEntry(worklog TempoWorklog) *tempoWorklogEntry {
+	entry := &tempoWorklogEntry{
+		IssueKey:         worklog.Ticket,
+		TimeSpentSeconds: int(worklog.TimeSpent.Seconds()),
+		StartDate:        worklog.Date,
+		StartTime:        tempoDefaultStartTime,
+		Description:      withFooter(worklog.Description),
+		AuthorAccountID:  config.Get(config.TempoAccountID),
+	}
+	if !worklog.Started.IsZero() {
+		entry.StartDate = worklog.Started.Format(tempoWorklogDateFormat)
+		entry.StartTime = worklog.Started.Format(tempoWorklogTimeFormat)
+	}
+	if worklog.Billable {
+		entry.BillableSeconds = entry.TimeSpentSeconds
+	}
+	if worklog.Account != "" {
+		entry.Attributes = append(entry.Attributes, tempoWorkAttributeDTO{Key: tempoAccountAttribute, Value: worklog.Account})
+	}
+	keys := make([]string, 0, len(worklog.Attributes))
+	for key := range worklog.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry.Attributes = append(entry.Attributes, tempoWorkAttributeDTO{Key: key, Value: worklog.Attributes[key]})
+	}
+	return entry
+}
+
+func (tempo *TempoAPIHTTPClient) postAuthenticated(path string, body io.Reader) (resp *http.Response, err error) {
+	serverURL := config.Get(config.TempoServerURL)
+	if serverURL == "" {
+		serverURL = defaultTempoServerURL
+	}
+
+	req, err := http.NewRequest("POST", serverURL+path, body)
+	if err != nil {
+		return
+	}
+
+	req.Header.Add("Authorization", "Bearer "+config.Get(config.TempoToken))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	return tempo.client.Do(req)
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTempoApi_LogWork(t *testing.T) {
+	expectedEntry := tempoWorklogEntry{
+		IssueKey:         "MGMT-1",
+		TimeSpentSeconds: 60,
+		BillableSeconds:  60,
+		StartDate:        "2020-05-22",
+		StartTime:        "09:00:00",
+		Description:      "Team catch-up\nAdded automatically by toggl-sync",
+		AuthorAccountID:  "account-1234",
+		Attributes: []tempoWorkAttributeDTO{
+			{Key: "_Account_", Value: "INTERNAL"},
+			{Key: "_Role_", Value: "Developer"},
+		},
+	}
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/worklogs",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, "Bearer tempo-token", r.Header.Get("Authorization"))
+				bytes, _ := io.ReadAll(r.Body)
+				var body tempoWorklogEntry
+				assert.Nil(t, json.Unmarshal(bytes, &body))
+				assert.Equal(t, expectedEntry, body)
+			},
+			ResponseCode: http.StatusOK,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TempoServerURL, server.URL)
+	config.Set(config.TempoToken, "tempo-token")
+	config.Set(config.TempoAccountID, "account-1234")
+
+	tempoAPI := NewTempoAPI()
+	err := tempoAPI.LogWork(TempoWorklog{
+		Ticket:      "MGMT-1",
+		TimeSpent:   time.Duration(60) * time.Second,
+		Date:        "2020-05-22",
+		Description: "Team catch-up",
+		Account:     "INTERNAL",
+		Billable:    true,
+		Attributes:  map[string]string{"_Role_": "Developer"},
+	})
+	assert.Nil(t, err)
+}
+
+func TestTempoApi_LogWork_StartTime(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/worklogs",
+			RequestValidator: func(r *http.Request) {
+				bytes, _ := io.ReadAll(r.Body)
+				var body tempoWorklogEntry
+				assert.Nil(t, json.Unmarshal(bytes, &body))
+				assert.Equal(t, "2020-05-23", body.StartDate)
+				assert.Equal(t, "00:30:00", body.StartTime)
+			},
+			ResponseCode: http.StatusOK,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TempoServerURL, server.URL)
+
+	tempoAPI := NewTempoAPI()
+	err := tempoAPI.LogWork(TempoWorklog{
+		Ticket:    "ENG-1001",
+		TimeSpent: time.Duration(60) * time.Second,
+		Date:      "2020-05-23",
+		Started:   time.Date(2020, 5, 22, 22, 30, 0, 0, time.UTC).In(madrid),
+	})
+	assert.Nil(t, err)
+}
+
+func TestTempoApi_LogWork_NotBillable(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/worklogs",
+			RequestValidator: func(r *http.Request) {
+				bytes, _ := io.ReadAll(r.Body)
+				var body tempoWorklogEntry
+				assert.Nil(t, json.Unmarshal(bytes, &body))
+				assert.Equal(t, 0, body.BillableSeconds)
+				assert.Equal(t, "Added automatically by toggl-sync", body.Description)
+				assert.Empty(t, body.Attributes)
+			},
+			ResponseCode: http.StatusOK,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TempoServerURL, server.URL)
+
+	tempoAPI := NewTempoAPI()
+	err := tempoAPI.LogWork(TempoWorklog{
+		Ticket:    "ENG-1001",
+		TimeSpent: time.Duration(60) * time.Second,
+		Date:      "2020-05-22",
+	})
+	assert.Nil(t, err)
+}
+
+func TestTempoApi_LogWork_ErrorWhenRequestFails(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/worklogs",
+			ResponseCode: http.StatusBadRequest,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TempoServerURL, server.URL)
+
+	tempoAPI := NewTempoAPI()
+	err := tempoAPI.LogWork(TempoWorklog{Ticket: "ENG-1001", TimeSpent: time.Minute, Date: "2020-05-22"})
+	assert.NotNilf(t, err, "API errors should be returned to the client")
+}
+
+func TestTempoApi_LogWork_ErrorWhenRequestErrors(t *testing.T) {
+	config.Set(config.TempoServerURL, "%#2")
+
+	tempoAPI := NewTempoAPI()
+	err := tempoAPI.LogWork(TempoWorklog{Ticket: "ENG-1001", TimeSpent: time.Minute, Date: "2020-05-22"})
+	assert.NotNil(t, err, "Request errors (e.g. misconfiguration) should be returned to the client")
+}
+
+func TestTempoApi_LogWork_ErrorWhenDateIsInvalid(t *testing.T) {
+	tempoAPI := NewTempoAPI()
+	err := tempoAPI.LogWork(TempoWorklog{Ticket: "ENG-1001", TimeSpent: time.Minute, Date: "22/05/2020"})
+	assert.NotNil(t, err)
+}
@@ -15,6 +15,9 @@ type TogglAPI interface {
 	GetMe() (*Me, error)
 	GetTimeEntries(startDate time.Time, endDate time.Time) ([]TimeEntry, error)
 	GetProjectById(id int) (*Project, error)
+	GetProjects() ([]ProjectData, error)
+	StopTimeEntry(id int) (*TimeEntry, error)
+	GetClients() ([]ClientData, error)
 }
 
 // TogglAPIHTTPClient is the implementation of TogglAPI using an HTTP client.
@@ -35,9 +38,11 @@ type Me struct {
 }
 
 // PersonalInfo contains personal information about the Toggl user.
+// Timezone is the IANA name of the time zone set in the user profile (e.g. Europe/Madrid).
 type PersonalInfo struct {
 	Email    string
 	Fullname string
+	Timezone string
 }
 
 // GetMe retrieves the user profile, using the Toggl credentials stored in the configuration file.
@@ -59,6 +64,9 @@ func (toggl *TogglAPIHTTPClient) GetMe() (*Me, error) {
 }
 
 // TimeEntry contains details about the entry recorded by the user, like description, duration and project/tags associated with it.
+// Running entries have a negative duration; Running marks those whose duration was counted up to now (see cmd/running.go).
+// Cid, Client and Color describe the project of the entry (Toggl does not send them with the entry; see cmd/exclude.go).
+// SourceIds lists the ids of the entries merged into this one, once summarized (see cmd/summarize.go).
 type TimeEntry struct {
 	Id          int
 	Pid         int
@@ -67,6 +75,12 @@ type TimeEntry struct {
 	Duration    int
 	Description string
 	Tags        []string
+	Billable    bool
+	Cid         int    `json:"-"`
+	Client      string `json:"-"`
+	Color       string `json:"-"`
+	Running     bool   `json:"-"`
+	SourceIds   []int  `json:"-"`
 }
 
 // GetTimeEntries retrieves all time entries within a given time period, represented by start and end.
@@ -98,8 +112,19 @@ type Project struct {
 	Data ProjectData
 }
 
-// ProjectData is a mapping of the project id to the project name.
+// ProjectData is a mapping of the project id to the project name, along with the client (Cid) it is done for.
+// Client is the name of the client, when Toggl sends it.
 type ProjectData struct {
+	Id       int
+	Name     string
+	Billable bool
+	Cid      int
+	Client   string `json:"client_name"`
+	Color    string `json:"hex_color"`
+}
+
+// ClientData is a mapping of the client id to the client name.
+type ClientData struct {
 	Id   int
 	Name string
 }
@@ -123,6 +148,79 @@ func (toggl *TogglAPIHTTPClient) GetProjectById(pid int) (*Project, error) {
 	return &data, resp.Body.Close()
 }
 
+// relatedData is the subset of the user profile (with related data) listing the projects of every workspace.
+type relatedData struct {
+	Data struct {
+		Projects []ProjectData
+	}
+}
+
+// GetProjects retrieves all projects the user has access to.
+// It uses the Toggl credentials stored in the configuration file.
+func (toggl *TogglAPIHTTPClient) GetProjects() ([]ProjectData, error) {
+	resp, err := toggl.getAuthenticatedWithQueryParams("/me", map[string]string{"with_related_data": "true"})
+	if err != nil {
+		return nil, fmt.Errorf("[GetProjects] Request failed! Error: %s", err)
+	} else if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("[GetProjects] Request failed with status: %d", resp.StatusCode)
+	}
+
+	var data relatedData
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("[GetProjects] Error unmarshalling response: %s", err)
+	}
+
+	return data.Data.Projects, resp.Body.Close()
+}
+
+// GetClients retrieves all clients the user has access to.
+// It uses the Toggl credentials stored in the configuration file.
+func (toggl *TogglAPIHTTPClient) GetClients() ([]ClientData, error) {
+	resp, err := toggl.getAuthenticated("/clients")
+	if err != nil {
+		return nil, fmt.Errorf("[GetClients] Request failed! Error: %s", err)
+	} else if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("[GetClients] Request failed with status: %d", resp.StatusCode)
+	}
+
+	var clients []ClientData
+	err = json.NewDecoder(resp.Body).Decode(&clients)
+	if err != nil {
+		return nil, fmt.Errorf("[GetClients] Error unmarshalling response: %s", err)
+	}
+
+	return clients, resp.Body.Close()
+}
+
+// timeEntryData is a wrapper over TimeEntry for data transfer.
+type timeEntryData struct {
+	Data TimeEntry
+}
+
+// StopTimeEntry stops the running time entry with the specified id, returning the stopped entry.
+// It uses the Toggl credentials stored in the configuration file.
+func (toggl *TogglAPIHTTPClient) StopTimeEntry(id int) (*TimeEntry, error) {
+	req, err := toggl.newAuthenticatedRequest("PUT", "/time_entries/"+strconv.Itoa(id)+"/stop")
+	if err != nil {
+		return nil, fmt.Errorf("[StopTimeEntry] Request failed! Error: %s", err)
+	}
+	resp, err := toggl.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("[StopTimeEntry] Request failed! Error: %s", err)
+	} else if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("[StopTimeEntry] Request failed with status: %d", resp.StatusCode)
+	}
+
+	var data timeEntryData
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("[StopTimeEntry] Error unmarshalling response: %s", err)
+	}
+
+	return &data.Data, resp.Body.Close()
+}
+
 func (toggl *TogglAPIHTTPClient) getAuthenticatedWithQueryParams(path string, params map[string]string) (*http.Response, error) {
 	req, err := http.NewRequest("GET", config.Get(config.TogglServerURL)+path, nil)
 	if err != nil {
@@ -142,7 +240,15 @@ func (toggl *TogglAPIHTTPClient) getAuthenticatedWithQueryParams(path string, pa
 }
 
 func (toggl *TogglAPIHTTPClient) getAuthenticated(path string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", config.Get(config.TogglServerURL)+path, nil)
+	req, err := toggl.newAuthenticatedRequest("GET", path)
+	if err != nil {
+		return nil, err
+	}
+	return toggl.client.Do(req)
+}
+
+func (toggl *TogglAPIHTTPClient) newAuthenticatedRequest(method string, path string) (*http.Request, error) {
+	req, err := http.NewRequest(method, config.Get(config.TogglServerURL)+path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -150,5 +256,5 @@ func (toggl *TogglAPIHTTPClient) getAuthenticated(path string) (*http.Response,
 	req.SetBasicAuth(config.Get(config.TogglUsername), config.Get(config.TogglPassword))
 
 	req.Header.Add("Accept", "application/json")
-	return toggl.client.Do(req)
+	return req, nil
 }
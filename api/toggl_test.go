@@ -15,6 +15,7 @@ func TestTogglApi_GetMe(t *testing.T) {
 		Data: PersonalInfo{
 			Email:    "tester@toggl-sync.com",
 			Fullname: "TogglSync Tester",
+			Timezone: "Europe/Madrid",
 		},
 	}
 
@@ -160,8 +161,11 @@ func TestTogglApi_GetProjectById(t *testing.T) {
 	projectId := 10
 	expectedProject := Project{
 		Data: ProjectData{
-			Id:   projectId,
-			Name: "Top Secret",
+			Id:       projectId,
+			Name:     "Top Secret",
+			Billable: true,
+			Cid:      3,
+			Color:    "#06aaf5",
 		},
 	}
 
@@ -226,3 +230,115 @@ func TestTogglApi_GetProjectById_ErrorWhenRequestErrors(t *testing.T) {
 	_, err := togglAPI.GetProjectById(10)
 	assert.NotNil(t, err, "Request errors (e.g. misconfiguration) should be returned to the client")
 }
+
+func TestTogglApi_GetProjects(t *testing.T) {
+	expectedProjects := []ProjectData{{Id: 10, Name: "Top Secret"}, {Id: 11, Name: "Meetings"}}
+	response := relatedData{}
+	response.Data.Projects = expectedProjects
+
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/me",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, "true", r.URL.Query().Get("with_related_data"))
+			},
+			ResponseCode: http.StatusOK,
+			ResponseBody: AsJSONString(response),
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TogglServerURL, server.URL)
+
+	togglAPI := NewTogglAPI()
+	projects, err := togglAPI.GetProjects()
+	assert.Nil(t, err)
+	assert.Equal(t, expectedProjects, projects)
+}
+
+func TestTogglApi_GetProjects_ErrorWhenRequestFails(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/me",
+			ResponseCode: http.StatusForbidden,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TogglServerURL, server.URL)
+
+	togglAPI := NewTogglAPI()
+	_, err := togglAPI.GetProjects()
+	assert.NotNilf(t, err, "API errors should be returned to the client")
+}
+
+func TestTogglApi_StopTimeEntry(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint: "/time_entries/42/stop",
+			RequestValidator: func(r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+			},
+			ResponseCode: http.StatusOK,
+			ResponseBody: `{"data": {"id": 42, "description": "ENG-1001", "duration": 1800}}`,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TogglServerURL, server.URL)
+
+	togglAPI := NewTogglAPI()
+	entry, err := togglAPI.StopTimeEntry(42)
+	assert.Nil(t, err)
+	assert.Equal(t, TimeEntry{Id: 42, Description: "ENG-1001", Duration: 1800}, *entry)
+}
+
+func TestTogglApi_StopTimeEntry_ErrorWhenRequestFails(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/time_entries/42/stop",
+			ResponseCode: http.StatusNotFound,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TogglServerURL, server.URL)
+
+	togglAPI := NewTogglAPI()
+	_, err := togglAPI.StopTimeEntry(42)
+	assert.NotNil(t, err, "API errors should be returned to the client")
+}
+
+func TestTogglApi_GetClients(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/clients",
+			ResponseCode: http.StatusOK,
+			ResponseBody: `[{"id": 3, "name": "ACME", "wid": 777}, {"id": 4, "name": "Initech", "wid": 777}]`,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TogglServerURL, server.URL)
+
+	togglAPI := NewTogglAPI()
+	clients, err := togglAPI.GetClients()
+	assert.Nil(t, err)
+	assert.Equal(t, []ClientData{{Id: 3, Name: "ACME"}, {Id: 4, Name: "Initech"}}, clients)
+}
+
+func TestTogglApi_GetClients_ErrorWhenRequestFails(t *testing.T) {
+	server := NewHTTPServer().
+		StubAPI(&Stubbing{
+			Endpoint:     "/clients",
+			ResponseCode: http.StatusForbidden,
+		}).
+		Create()
+	defer server.Close()
+
+	config.Set(config.TogglServerURL, server.URL)
+
+	togglAPI := NewTogglAPI()
+	_, err := togglAPI.GetClients()
+	assert.NotNil(t, err, "API errors should be returned to the client")
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
+)
+
+// Default worklog comments: the description of overhead work, and the one of project work only when it says more than the ticket
+const (
+	defaultProjectComment  = `{{if ne .Description .Ticket}}{{.Description}}{{end}}`
+	defaultOverheadComment = `{{.Description}}`
+)
+
+var commentFuncs = template.FuncMap{"join": strings.Join}
+
+// commentData is what comment templates are rendered with (see config.SyncCommentProject and config.SyncCommentOverhead).
+// Project and Client are only retrieved when a template uses them (once per sync, see projectCache).
+type commentData struct {
+	Description string
+	Ticket      string
+	Tags        []string
+	Start       time.Time
+	Stop        time.Time
+	Duration    time.Duration
+	EntryIDs    []int
+	Overhead    bool
+
+	timeSource source.TimeSource
+	projects   *projectCache
+	pid        int
+}
+
+// Project returns the name of the Toggl project of the entry (empty if it has none)
+func (data commentData) Project() (string, error) {
+	project, err := data.project()
+	if project == nil {
+		return "", err
+	}
+	return project.Data.Name, nil
+}
+
+// Client returns the name of the client the project of the entry is done for (empty if there is none, or the source provides no clients)
+func (data commentData) Client() (string, error) {
+	project, err := data.project()
+	if project == nil {
+		return "", err
+	} else if project.Data.Client != "" {
+		return project.Data.Client, nil
+	}
+	return data.projects.clientName(data.timeSource, project.Data.Cid)
+}
+
+func (data commentData) project() (*api.Project, error) {
+	if data.pid == 0 {
+		return nil, nil
+	}
+	return data.projects.project(data.timeSource, data.pid)
+}
+
+// worklogComment renders the comment template of the worklog (project or overhead work) with the details of the entry it comes from
+func worklogComment(timeSource source.TimeSource, projects *projectCache, location *time.Location, worklog sink.Worklog, entry api.TimeEntry) (string, error) {
+	key, text := config.SyncCommentProject, configOrDefault(config.SyncCommentProject, defaultProjectComment)
+	if worklog.Overhead {
+		key, text = config.SyncCommentOverhead, configOrDefault(config.SyncCommentOverhead, defaultOverheadComment)
+	}
+	tmpl, err := parseComment(key, text)
+	if err != nil {
+		return "", fmt.Errorf("invalid comment template [%s]: %s", key, err)
+	}
+
+	data := commentData{
+		Description: entry.Description,
+		Ticket:      worklog.Ticket,
+		Tags:        entry.Tags,
+		Start:       worklog.Started,
+		Duration:    worklog.TimeSpent,
+		EntryIDs:    entry.SourceIds,
+		Overhead:    worklog.Overhead,
+		timeSource:  timeSource,
+		projects:    projects,
+		pid:         entry.Pid,
+	}
+	if !entry.Stop.IsZero() {
+		data.Stop = entry.Stop.In(location)
+	}
+	if len(data.EntryIDs) == 0 {
+		data.EntryIDs = []int{entry.Id}
+	}
+
+	var comment bytes.Buffer
+	if err = tmpl.Execute(&comment, data); err != nil {
+		return "", fmt.Errorf("error rendering comment template [%s]: %s", key, err)
+	}
+	return strings.TrimSpace(comment.String()), nil
+}
+
+func parseComment(key string, text string) (*template.Template, error) {
+	return template.New(key).Funcs(commentFuncs).Option("missingkey=error").Parse(text)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCmd_CommentTemplates(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncTimezone, "UTC")
+	config.Set(config.SyncCommentProject, "{{.Description}} [{{join .Tags \", \"}}] for {{.Client}}")
+	config.Set(config.SyncCommentOverhead, "{{.Project}}: {{.Description}} ({{.Start.Format \"15:04\"}}-{{.Stop.Format \"15:04\"}}, entries {{.EntryIDs}})")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+	start := time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC)
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Pid: 7, Duration: 240, Description: "ENG-1001", Tags: []string{"review", "backend"}},
+			{Id: 2, Pid: 8, Start: start, Stop: start.Add(time.Hour), Duration: 3600, Description: "Team catch-up"},
+		},
+		Project: api.Project{Data: api.ProjectData{Id: 8, Name: "Meetings", Cid: 3}},
+		Clients: []api.ClientData{{Id: 3, Name: "ACME"}},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001 [review, backend] for ACME", 240))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("Meetings: Team catch-up (09:00-10:00, entries [2])", 3600))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestRootCmd_CommentTemplates_ClientsRetrievedOnce(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncCommentProject, "{{.Description}} for {{.Client}}")
+	togglAPI := &clientCountingTogglAPI{MockTogglAPI: MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Pid: 7, Duration: 240, Description: "ENG-1001"},
+			{Id: 2, Pid: 7, Duration: 60, Description: "ENG-1002"},
+		},
+		Project: api.Project{Data: api.ProjectData{Id: 7, Name: "Website", Cid: 3}},
+		Clients: []api.ClientData{{Id: 3, Name: "ACME"}},
+	}}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	assert.Nil(t, cmd.Execute())
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001 for ACME", 240))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1002 for ACME", 60))
+	assert.Equal(t, 1, togglAPI.ClientCalls)
+}
+
+func TestWorklogComment_Defaults(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{}
+
+	tests := []struct {
+		name     string
+		entry    api.TimeEntry
+		overhead bool
+		expected string
+	}{
+		{"project work", api.TimeEntry{Description: "ENG-1001"}, false, ""},
+		{"merged project work", api.TimeEntry{Description: "ENG-1001 Review; ENG-1001 Fixes"}, false, "ENG-1001 Review; ENG-1001 Fixes"},
+		{"overhead work", api.TimeEntry{Description: "Team catch-up"}, true, "Team catch-up"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			worklog := sink.Worklog{Ticket: "ENG-1001", Description: test.entry.Description, Overhead: test.overhead}
+
+			comment, err := worklogComment(togglAPI, newProjectCache(), time.UTC, worklog, test.entry)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, comment)
+		})
+	}
+}
+
+func TestWorklogComment_InvalidTemplate(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncCommentProject, "{{.Unknown}}")
+
+	_, err := worklogComment(&MockTogglAPI{}, newProjectCache(), time.UTC, sink.Worklog{Ticket: "ENG-1001"}, api.TimeEntry{Description: "ENG-1001"})
+	assert.EqualError(t, err, "error rendering comment template [sync.comment.project]: template: sync.comment.project:1:2: executing \"sync.comment.project\" at <.Unknown>: can't evaluate field Unknown in type cmd.commentData")
+}
+
+func TestConfigSetCmd_InvalidCommentTemplate(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"set", config.SyncCommentOverhead, "{{.Description"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "invalid value for [sync.comment.overhead]: template: sync.comment.overhead:1: unclosed action")
+}
+
+type clientCountingTogglAPI struct {
+	MockTogglAPI
+	ClientCalls int
+}
+
+func (mock *clientCountingTogglAPI) GetClients() ([]api.ClientData, error) {
+	mock.ClientCalls++
+	return mock.MockTogglAPI.GetClients()
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/schedule"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+const secretMask = "********"
+
+var (
+	jiraProjectKeyFormat = regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`)
+	jiraTicketFormat     = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-[0-9]+$`)
+)
+
+// NewConfigCmd creates a new Cobra Command that reads and updates single configuration values
+func NewConfigCmd(configManager config.Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and update single configuration values",
+		Long:  "Read and update single configuration values, without going through the whole 'configure' flow",
+	}
+	cmd.AddCommand(newConfigGetCmd(configManager))
+	cmd.AddCommand(newConfigSetCmd(configManager))
+	cmd.AddCommand(newConfigUnsetCmd(configManager))
+	cmd.AddCommand(newConfigListCmd(configManager))
+	cmd.AddCommand(newConfigEditCmd(configManager))
+	return cmd
+}
+
+func newConfigGetCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a configuration key",
+		Long:  "Print the value of a configuration key (multiple values are separated by commas)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			key := strings.ToLower(args[0])
+			if !config.IsSet(key) {
+				return fmt.Errorf("config key [%s] has no value", key)
+			}
+			_, err := fmt.Fprintln(cmd.OutOrStdout(), configValue(key))
+			return err
+		},
+	}
+}
+
+func newConfigSetCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Update the value of a configuration key",
+		Long: "Update the value of a configuration key. Values are validated before being saved: " +
+			"URLs, Jira project keys, sinks, sources, schedules and numbers. " +
+			"Multiple values (e.g. jira.project.key) are separated by commas.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			key, value := strings.ToLower(args[0]), args[1]
+			if err := validateConfigValue(key, value); err != nil {
+				return err
+			}
+			switch {
+			case isMultiValueKey(key):
+				config.Set(key, strings.Split(value, ","))
+			case isNumericKey(key):
+				number, _ := strconv.Atoi(value)
+				config.Set(key, number)
+			default:
+				config.Set(key, value)
+			}
+
+			if err := configManager.Persist(); err != nil {
+				return fmt.Errorf("error saving configuration to file: %s", err)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigUnsetCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a configuration key",
+		Long:  "Remove a configuration key (along with any key nested under it, e.g. 'jira.instances.ops')",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			key := strings.ToLower(args[0])
+			if !config.Unset(key) {
+				return fmt.Errorf("config key [%s] has no value", key)
+			}
+
+			if err := configManager.Persist(); err != nil {
+				return fmt.Errorf("error saving configuration to file: %s", err)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigListCmd(configManager config.Manager) *cobra.Command {
+	var showSecrets bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all configuration values",
+		Long: "List all configuration values of the profile in use, marking those supplied by the team configuration file " +
+			"(credentials are masked, unless --show-secrets is provided)",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			for _, key := range config.Keys() {
+				value := configValue(key)
+				if config.IsSecret(key) && !showSecrets {
+					value = secretMask
+				}
+				if config.Origin(key) == config.OriginTeam {
+					value += " (team)"
+				}
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", key, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "print credentials in plain text")
+	return cmd
+}
+
+func newConfigEditCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the configuration file in an editor",
+		Long:  "Open the configuration file in the editor set in $EDITOR (vi by default), checking it can still be read afterwards",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := initExistingConfig(configManager); err != nil {
+				return err
+			}
+
+			editor := os.Getenv("EDITOR")
+			if editor == "" {
+				editor = "vi"
+			}
+			editorCmd := exec.Command(editor, config.FileUsed())
+			editorCmd.Stdin = os.Stdin
+			editorCmd.Stdout = cmd.OutOrStdout()
+			editorCmd.Stderr = cmd.ErrOrStderr()
+			if err := editorCmd.Run(); err != nil {
+				return fmt.Errorf("error running editor [%s]: %s", editor, err)
+			}
+
+			if _, err := configManager.Init(); err != nil {
+				return fmt.Errorf("configuration file is no longer valid: %s", err)
+			}
+			return nil
+		},
+	}
+}
+
+// configValue returns the value of the key as text (multiple values are separated by commas)
+func configValue(key string) string {
+	if key == config.OverheadMappings {
+		return fmt.Sprintf("%d mapping(s), see 'overhead list'", len(config.GetOverheadMappings()))
+	} else if isMultiValueKey(key) {
+		return strings.Join(config.GetSlice(key), ",")
+	}
+	return config.Get(key)
+}
+
+func isMultiValueKey(key string) bool {
+	switch key {
+	case config.JiraProjectKey, config.SyncSinks, config.SyncSources, config.SyncExcludeTags, config.SyncExcludeProjects:
+		return true
+	}
+	return isJiraInstanceKey(key, config.JiraProjectKey) ||
+		strings.HasPrefix(key, "tempo.mappings.") && strings.HasSuffix(key, "."+config.TempoMappingAttributes)
+}
+
+func isNumericKey(key string) bool {
+	return key == config.StatusDays || key == config.DaemonCatchUpDays || key == config.Backups
+}
+
+func isJiraInstanceKey(key string, setting string) bool {
+	return strings.HasPrefix(key, "jira.instances.") && strings.HasSuffix(key, "."+strings.TrimPrefix(setting, "jira."))
+}
+
+// validateConfigValue checks the value has the format expected for the key (keys with no known format accept any value)
+func validateConfigValue(key string, value string) error {
+	if value == "" {
+		return fmt.Errorf("invalid value for [%s]: value cannot be empty (use 'config unset %s' to remove it)", key, key)
+	}
+
+	switch {
+	case key == config.Version:
+		return fmt.Errorf("invalid value for [%s]: the configuration version is managed by toggl-sync", key)
+	case key == config.TeamConfigURL:
+		// Local paths are accepted too
+		return nil
+	case key == config.TeamConfigCacheTTL:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid value for [%s]: [%s] is not a valid duration (e.g. 30m)", key, value)
+		}
+	case key == config.SyncTimezone:
+		if _, err := time.LoadLocation(value); err != nil {
+			return fmt.Errorf("invalid value for [%s]: [%s] is not a valid time zone (e.g. Europe/Madrid)", key, value)
+		}
+	case strings.HasSuffix(key, ".url"):
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid value for [%s]: [%s] is not a valid http(s) URL", key, value)
+		}
+	case key == config.JiraProjectKey || isJiraInstanceKey(key, config.JiraProjectKey):
+		for _, projectKey := range strings.Split(value, ",") {
+			if !jiraProjectKeyFormat.MatchString(projectKey) {
+				return fmt.Errorf("invalid value for [%s]: [%s] is not a valid Jira project key (e.g. ENG)", key, projectKey)
+			}
+		}
+	case key == config.OverheadMappings:
+		return fmt.Errorf("invalid value for [%s]: overhead tickets are managed with the 'overhead' command", key)
+	case key == config.SyncExcludeDescription:
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid value for [%s]: [%s] is not a valid regular expression", key, value)
+		}
+	case key == config.SyncCommentProject || key == config.SyncCommentOverhead:
+		if _, err := parseComment(key, value); err != nil {
+			return fmt.Errorf("invalid value for [%s]: %s", key, err)
+		}
+	case key == config.SyncExcludeBillable || key == config.SyncCommentFooterEnabled:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid value for [%s]: [%s] is not true or false", key, value)
+		}
+	case key == config.WorklogVisibilities || strings.HasPrefix(key, config.WorklogVisibilities+"."):
+		return fmt.Errorf("invalid value for [%s]: worklog visibility rules are edited in the configuration file (see 'config edit')", key)
+	case key == config.SyncRunning:
+		return validateNames(key, value, runningModes...)
+	case key == config.SyncSummarize:
+		return validateNames(key, value, summarizeStrategies...)
+	case key == config.SyncSinks:
+		return validateNames(key, value, sink.Jira, sink.CSV, sink.JSON, sink.Tempo)
+	case key == config.SyncSources:
+		return validateNames(key, value, source.Toggl, source.Clockify, source.CSV, source.ICS)
+	case key == config.DaemonSchedule:
+		if _, err := schedule.Parse(value); err != nil {
+			return fmt.Errorf("invalid value for [%s]: %s", key, err)
+		}
+	case isNumericKey(key):
+		if number, err := strconv.Atoi(value); err != nil || number < 0 {
+			return fmt.Errorf("invalid value for [%s]: [%s] is not a positive number", key, value)
+		}
+	}
+	return nil
+}
+
+// validateVisibilityRules checks every worklog visibility rule targets a project or ticket, and names a group or role
+func validateVisibilityRules() error {
+	for i, rule := range config.GetVisibilityRules() {
+		key := fmt.Sprintf("%s[%d]", config.WorklogVisibilities, i)
+		if rule.ProjectID == 0 && rule.Project == "" && rule.Ticket == "" {
+			return fmt.Errorf("invalid value for [%s]: a projectId, project or ticket is required", key)
+		}
+		if err := validateNames(key+".type", rule.Type, api.VisibilityGroup, api.VisibilityRole); err != nil {
+			return err
+		}
+		if rule.Value == "" {
+			return fmt.Errorf("invalid value for [%s.value]: the name of the %s is required", key, rule.Type)
+		}
+	}
+	return nil
+}
+
+func validateNames(key string, value string, known ...string) error {
+	for _, name := range strings.Split(value, ",") {
+		if !contains(known, name) {
+			return fmt.Errorf("invalid value for [%s]: [%s] is not one of %s", key, name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigGetCmd(t *testing.T) {
+	setupBasicConfig()
+	output := bytes.NewBufferString("")
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"get", "jira.project.key"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ENG,MGMT\n", output.String())
+}
+
+func TestConfigGetCmd_UnknownKey(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"get", "jira.unknown"})
+	err := cmd.Execute()
+
+	assert.EqualError(t, err, "config key [jira.unknown] has no value")
+}
+
+func TestConfigSetCmd(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"set", "jira.server.url", "https://jira.example.com"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://jira.example.com", config.Get(config.JiraServerURL))
+}
+
+func TestConfigSetCmd_MultipleValuesAndNumbers(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"set", "jira.project.key", "ENG,OPS"})
+	assert.Nil(t, cmd.Execute())
+	cmd.SetArgs([]string{"set", "status.days", "14"})
+	assert.Nil(t, cmd.Execute())
+
+	assert.Equal(t, []string{"ENG", "OPS"}, config.GetSlice(config.JiraProjectKey))
+	assert.Equal(t, 14, config.GetInt(config.StatusDays))
+}
+
+func TestConfigSetCmd_InvalidValues(t *testing.T) {
+	tests := map[string][]string{
+		"invalid value for [jira.server.url]: [jira.example.com] is not a valid http(s) URL":                                     {"jira.server.url", "jira.example.com"},
+		"invalid value for [jira.project.key]: [eng] is not a valid Jira project key (e.g. ENG)":                                 {"jira.project.key", "OPS,eng"},
+		"invalid value for [jira.overheads]: overhead tickets are managed with the 'overhead' command":                           {"jira.overheads", "MGMT-1"},
+		"invalid value for [config.version]: the configuration version is managed by toggl-sync":                                 {"config.version", "1"},
+		"invalid value for [sync.sinks]: [excel] is not one of jira, csv, json, tempo":                                           {"sync.sinks", "jira,excel"},
+		"invalid value for [daemon.catch.up.days]: [two] is not a positive number":                                               {"daemon.catch.up.days", "two"},
+		"invalid value for [sync.exclude.billable]: [maybe] is not true or false":                                                {"sync.exclude.billable", "maybe"},
+		"invalid value for [jira.visibility]: worklog visibility rules are edited in the configuration file (see 'config edit')": {"jira.visibility", "group"},
+		"invalid value for [sync.timezone]: [Mars/Olympus_Mons] is not a valid time zone (e.g. Europe/Madrid)":                   {"sync.timezone", "Mars/Olympus_Mons"},
+		"invalid value for [toggl.username]: value cannot be empty (use 'config unset toggl.username' to remove it)":             {"toggl.username", ""},
+	}
+	for message, args := range tests {
+		setupBasicConfig()
+
+		cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+		cmd.SetArgs(append([]string{"set"}, args...))
+		err := cmd.Execute()
+
+		assert.EqualError(t, err, message)
+	}
+	assert.Equal(t, []string{"ENG", "MGMT"}, config.GetSlice(config.JiraProjectKey))
+}
+
+func TestConfigSetCmd_ErrorPersistingConfig(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true, PersistError: errors.New("stub error persisting config")})
+	cmd.SetArgs([]string{"set", "toggl.username", "AnotherUser"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
+
+func TestConfigUnsetCmd(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"unset", "jira.overheads"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Empty(t, config.GetOverheadMappings())
+	assert.Equal(t, "JiraUser", config.Get(config.JiraUsername))
+}
+
+func TestConfigUnsetCmd_UnknownKey(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"unset", "jira.overheads"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
+
+func TestConfigListCmd(t *testing.T) {
+	setupBasicConfig()
+	output := bytes.NewBufferString("")
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"list"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"jira.password=********\n"+
+		"jira.project.key=ENG,MGMT\n"+
+		"jira.server.url=http://localhost/jira\n"+
+		"jira.username=JiraUser\n"+
+		"toggl.password=********\n"+
+		"toggl.server.url=http://localhost/toggl\n"+
+		"toggl.username=TogglUser\n",
+		output.String())
+}
+
+func TestConfigListCmd_ShowSecrets(t *testing.T) {
+	setupBasicConfig()
+	output := bytes.NewBufferString("")
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"list", "--show-secrets"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "jira.password=JiraPassword\n")
+}
+
+func TestConfigEditCmd(t *testing.T) {
+	setupBasicConfig()
+	t.Setenv("EDITOR", "true")
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"edit"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+}
+
+func TestConfigEditCmd_EditorFails(t *testing.T) {
+	setupBasicConfig()
+	t.Setenv("EDITOR", "false")
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"edit"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
+
+func TestConfigListCmd_TeamValues(t *testing.T) {
+	setupBasicConfig()
+	path := filepath.Join(t.TempDir(), "team.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte("jira:\n  project:\n    key: [OPS]\n  overheads:\n    - project: Support\n      ticket: OPS-7\n"), 0600))
+	config.Set(config.TeamConfigURL, path)
+	config.Unset(config.JiraProjectKey)
+	output := bytes.NewBufferString("")
+
+	cmd := NewConfigCmd(&MockConfigManager{InitOk: true})
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"list"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "jira.overheads=1 mapping(s), see 'overhead list' (team)\n")
+	assert.Contains(t, output.String(), "jira.project.key=OPS (team)\n")
+	assert.Contains(t, output.String(), "team.config.url="+path+"\n")
+}
+
+func TestRootCmd_WorklogVisibility(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{ProjectID: 7, Project: "Interviews", Ticket: "MGMT-1"})
+	config.Set(config.WorklogVisibilities, []map[string]interface{}{{"projectId": 7, "type": api.VisibilityGroup, "value": "hr"}})
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{{Id: 1, Pid: 7, Duration: 3600, Description: "Interview"}},
+		Project:     api.Project{Data: api.ProjectData{Id: 7, Name: "Interviews"}},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	assert.Nil(t, cmd.Execute())
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("Interview", 3600))
+	assert.Equal(t, map[string]api.WorklogVisibility{"MGMT-1": {Type: api.VisibilityGroup, Value: "hr"}}, jiraAPI.Visibilities)
+}
+
+func TestRootCmd_InvalidWorklogVisibility(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"invalid value for [jira.visibility[0]]: a projectId, project or ticket is required": {"type": api.VisibilityGroup, "value": "hr"},
+		"invalid value for [jira.visibility[0].type]: [team] is not one of group, role":      {"ticket": "MGMT-1", "type": "team", "value": "hr"},
+		"invalid value for [jira.visibility[0].value]: the name of the group is required":    {"project": "Interviews", "type": api.VisibilityGroup},
+	}
+	for message, rule := range tests {
+		setupBasicConfig()
+		config.Set(config.WorklogVisibilities, []map[string]interface{}{rule})
+
+		cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+		cmd.SetArgs([]string{"2020-05-22"})
+		assert.EqualError(t, cmd.Execute(), message)
+	}
+}
@@ -15,18 +15,23 @@ func NewConfigureCmd(configManager config.Manager, inputCtrl inputController) *c
 		Short: "Create (or update) toggl-sync configuration",
 		Long:  "Create (or update) the necessary configuration entries so all other toggl-sync commands work without issues",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := configure(configManager, inputCtrl)
+			err := configure(configManager, inputCtrl, profileFlag(cmd))
 			return err
 		},
 	}
 }
 
-func configure(configManager config.Manager, inputCtrl inputController) error {
+func configure(configManager config.Manager, inputCtrl inputController, profile string) error {
 	_, err := configManager.Init()
 	if err != nil {
 		return fmt.Errorf("error reading configuration file: %s", err)
 	}
 
+	if profile == "" {
+		profile = config.GetDefaultProfile()
+	}
+	config.UseProfile(profile)
+
 	err = updateConfiguration(inputCtrl)
 	if err != nil {
 		return fmt.Errorf("error updating configuration: %s", err)
@@ -65,14 +70,35 @@ func updateConfiguration(inputCtrl inputController) (err error) {
 	if err != nil {
 		return
 	}
-	for _, key := range config.GetAllOverheadKeys() {
-		if err = saveOverheadSettingAs(inputCtrl, fmt.Sprintf("Overhead - %s", key), key); err != nil {
+	for _, instance := range config.GetJiraInstances() {
+		if err = updateJiraInstanceConfiguration(inputCtrl, instance); err != nil {
+			return
+		}
+	}
+	for _, mapping := range config.GetOverheadMappings() {
+		if err = saveOverheadSettingAs(inputCtrl, fmt.Sprintf("Overhead - %s", mapping.Project), mapping); err != nil {
 			return
 		}
 	}
 	return
 }
 
+func updateJiraInstanceConfiguration(inputCtrl inputController, instance string) (err error) {
+	err = saveSingleValueSettingAs(inputCtrl, fmt.Sprintf("Jira (%s) server url", instance), config.JiraInstanceKey(instance, config.JiraServerURL), false)
+	if err != nil {
+		return
+	}
+	err = saveSingleValueSettingAs(inputCtrl, fmt.Sprintf("Jira (%s) username", instance), config.JiraInstanceKey(instance, config.JiraUsername), false)
+	if err != nil {
+		return
+	}
+	err = saveSingleValueSettingAs(inputCtrl, fmt.Sprintf("Jira (%s) password", instance), config.JiraInstanceKey(instance, config.JiraPassword), true)
+	if err != nil {
+		return
+	}
+	return saveMultiValueSettingAs(inputCtrl, fmt.Sprintf("Jira (%s) project key(s)", instance), config.JiraInstanceKey(instance, config.JiraProjectKey), false)
+}
+
 func saveMultiValueSettingAs(inputCtrl inputController, inputName string, key string, isPassword bool) error {
 	existingValue := strings.Join(config.GetSlice(key), ",")
 	input, err := requestInput(inputCtrl, inputName, existingValue, isPassword)
@@ -91,11 +117,11 @@ func saveSingleValueSettingAs(inputCtrl inputController, inputName string, key s
 	return err
 }
 
-func saveOverheadSettingAs(inputCtrl inputController, inputName string, key string) error {
-	existingValue := config.GetOverheadKey(key)
-	input, err := requestTextInput(inputCtrl, inputName, existingValue)
+func saveOverheadSettingAs(inputCtrl inputController, inputName string, mapping config.OverheadMapping) error {
+	input, err := requestTextInput(inputCtrl, inputName, mapping.Ticket)
 	if err == nil && input != "" {
-		config.SetOverheadKey(key, input)
+		mapping.Ticket = input
+		config.SetOverheadMapping(mapping)
 	}
 	return err
 }
@@ -110,8 +110,8 @@ func TestConfigureCmd_PreserveExistingValuesOnEmptyInput(t *testing.T) {
 }
 
 func TestConfigureCmd_OverrideOverheadKeys(t *testing.T) {
-	config.SetOverheadKey("meetings", "ENG-1234")
-	config.SetOverheadKey("cooking", "ENG-1007")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "meetings", Ticket: "ENG-1234"})
+	config.SetOverheadMapping(config.OverheadMapping{Project: "cooking", Ticket: "ENG-1007"})
 	cmd := NewConfigureCmd(&MockConfigManager{}, &MockInputController{
 		TextInput: "value",
 		Password:  "secret",
@@ -119,13 +119,13 @@ func TestConfigureCmd_OverrideOverheadKeys(t *testing.T) {
 	err := cmd.Execute()
 
 	assert.Nil(t, err)
-	assert.Equal(t, "value", config.GetOverheadKey("meetings"))
-	assert.Equal(t, "value", config.GetOverheadKey("cooking"))
+	assert.Equal(t, "value", config.GetOverheadTicket(0, "meetings"))
+	assert.Equal(t, "value", config.GetOverheadTicket(0, "cooking"))
 }
 
 func TestConfigureCmd_PreserveOverheadKeysOnEmptyInput(t *testing.T) {
-	config.SetOverheadKey("meetings", "ENG-1234")
-	config.SetOverheadKey("cooking", "ENG-1007")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "meetings", Ticket: "ENG-1234"})
+	config.SetOverheadMapping(config.OverheadMapping{Project: "cooking", Ticket: "ENG-1007"})
 	cmd := NewConfigureCmd(&MockConfigManager{}, &MockInputController{
 		TextInput: "",
 		Password:  "secret",
@@ -133,8 +133,8 @@ func TestConfigureCmd_PreserveOverheadKeysOnEmptyInput(t *testing.T) {
 	err := cmd.Execute()
 
 	assert.Nil(t, err)
-	assert.Equal(t, "ENG-1234", config.GetOverheadKey("meetings"))
-	assert.Equal(t, "ENG-1007", config.GetOverheadKey("cooking"))
+	assert.Equal(t, "ENG-1234", config.GetOverheadTicket(0, "meetings"))
+	assert.Equal(t, "ENG-1007", config.GetOverheadTicket(0, "cooking"))
 }
 
 func TestConfigureCmd_PropagateErrorWhenReadingTogglUsernameFails(t *testing.T) {
@@ -184,8 +184,8 @@ func TestConfigureCmd_PropagateErrorWhenReadingJiraProjectKeyFails(t *testing.T)
 }
 
 func TestConfigureCmd_PropagateErrorWhenReadingOverheadKey(t *testing.T) {
-	config.SetOverheadKey("meetings", "ENG-1234")
-	config.SetOverheadKey("cooking", "ENG-1007")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "meetings", Ticket: "ENG-1234"})
+	config.SetOverheadMapping(config.OverheadMapping{Project: "cooking", Ticket: "ENG-1007"})
 	cmd := NewConfigureCmd(&MockConfigManager{}, &MockInputController{
 		TextInput:          "value",
 		FailTextInputAfter: 4,
@@ -232,3 +232,40 @@ func TestConfigureCmd_ErrorPersistingConfig(t *testing.T) {
 
 	assert.NotNil(t, err)
 }
+
+func TestConfigureCmd_OverrideJiraInstanceValues(t *testing.T) {
+	config.Reset()
+	defer config.Reset()
+	config.Set(config.JiraInstanceKey("ops", config.JiraServerURL), "http://localhost/ops")
+	cmd := NewConfigureCmd(&MockConfigManager{}, &MockInputController{
+		TextInput: "value",
+		Password:  "secret",
+	})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "value", config.Get(config.JiraInstanceKey("ops", config.JiraServerURL)))
+	assert.Equal(t, "value", config.Get(config.JiraInstanceKey("ops", config.JiraUsername)))
+	assert.Equal(t, "secret", config.Get(config.JiraInstanceKey("ops", config.JiraPassword)))
+	assert.Equal(t, "value", config.GetSlice(config.JiraInstanceKey("ops", config.JiraProjectKey))[0])
+}
+
+func TestConfigureCmd_Profile(t *testing.T) {
+	config.Reset()
+	defer config.Reset()
+	configManager := &MockConfigManager{}
+	inputCtrl := &MockInputController{
+		TextInput: "value",
+		Password:  "secret",
+	}
+	rootCmd := NewRootCmd(configManager, inputCtrl, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	rootCmd.AddCommand(NewConfigureCmd(configManager, inputCtrl))
+	rootCmd.SetArgs([]string{"configure", "--profile", "clienta"})
+	err := rootCmd.Execute()
+
+	assert.Nil(t, err)
+	assert.True(t, config.HasProfile("clienta"))
+	assert.Equal(t, "value", config.Get(config.TogglUsername))
+	config.UseProfile("")
+	assert.Equal(t, "", config.Get(config.TogglUsername))
+}
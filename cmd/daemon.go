@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/schedule"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultDaemonSchedule    = "every weekday at 18:00"
+	defaultDaemonCatchUpDays = 7
+)
+
+// NewDaemonCmd creates a new Cobra Command that synchronizes time entries automatically, following a schedule
+func NewDaemonCmd(configManager config.Manager, sources map[string]source.TimeSource, sinks map[string]sink.WorklogSink, syncLedger ledger.Ledger, clock schedule.Clock) *cobra.Command {
+	var scheduleExpression string
+	var catchUpDays int
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Synchronize time entries automatically, following a schedule",
+		Long: "Synchronize time entries automatically, following a schedule (e.g. \"every weekday at 18:00\" or \"0 18 * * 1-5\"). " +
+			"Days missed while the daemon was not running are synced on start-up.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, timezone := profileFlag(cmd), timezoneFlag(cmd)
+			if err := readConfig(configManager, profile); err != nil {
+				return err
+			}
+
+			if scheduleExpression == "" {
+				scheduleExpression = configOrDefault(config.DaemonSchedule, defaultDaemonSchedule)
+			}
+			syncSchedule, err := schedule.Parse(scheduleExpression)
+			if err != nil {
+				return err
+			}
+			if catchUpDays == 0 && config.IsSet(config.DaemonCatchUpDays) {
+				catchUpDays = config.GetInt(config.DaemonCatchUpDays)
+			} else if catchUpDays == 0 {
+				catchUpDays = defaultDaemonCatchUpDays
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			syncMissedDates := func() {
+				location, err := daemonLocation(configManager, profile, timezone, sources)
+				if err != nil {
+					log.Printf("Sync of missed dates failed with an error: %s", err)
+					return
+				}
+				now := clock.Now()
+				for _, date := range scheduledDates(syncSchedule, now.AddDate(0, 0, -catchUpDays), now, location) {
+					if err := syncScheduledDate(configManager, profile, timezone, sources, sinks, syncLedger, date); err != nil {
+						log.Printf("Sync of [%s] failed with an error: %s", date, err)
+					}
+				}
+			}
+
+			log.Printf("Daemon started (schedule: %s)", scheduleExpression)
+			syncMissedDates()
+			schedule.NewScheduler(clock, syncSchedule).Run(ctx, func(time.Time) {
+				syncMissedDates()
+			})
+			log.Print("Daemon stopped")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&scheduleExpression, "schedule", "", fmt.Sprintf("sync schedule, as a cron expression or in plain English (default \"%s\")", defaultDaemonSchedule))
+	cmd.Flags().IntVar(&catchUpDays, "catch-up-days", 0, fmt.Sprintf("number of past days checked for missed syncs (default %d)", defaultDaemonCatchUpDays))
+	return cmd
+}
+
+// scheduledDates returns the (distinct) dates, in the location provided, of all activations of the schedule within the period (from, to]
+func scheduledDates(syncSchedule schedule.Schedule, from time.Time, to time.Time, location *time.Location) []string {
+	var dates []string
+	for _, activation := range schedule.Activations(syncSchedule, from, to) {
+		date := activation.In(location).Format("2006-01-02")
+		if len(dates) == 0 || dates[len(dates)-1] != date {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// daemonLocation returns the time zone of the user (see userLocation), reading the config again as a sync would
+func daemonLocation(configManager config.Manager, profile string, timezone string, sources map[string]source.TimeSource) (*time.Location, error) {
+	if err := readConfig(configManager, profile); err != nil {
+		return nil, err
+	}
+	timeSource, err := selectSources(sources, configuredSources(nil))
+	if err != nil {
+		return nil, err
+	}
+	return userLocation(timeSource, timezone)
+}
+
+func syncScheduledDate(configManager config.Manager, profile string, timezone string, sources map[string]source.TimeSource, sinks map[string]sink.WorklogSink, syncLedger ledger.Ledger, syncDate string) error {
+	if err := readConfig(configManager, profile); err != nil {
+		return err
+	}
+
+	sourceNames := configuredSources(nil)
+	if err := validateConfig(sourceNames); err != nil {
+		return err
+	}
+	timeSource, err := selectSources(sources, sourceNames)
+	if err != nil {
+		return err
+	}
+	return sync(unattendedInputController{}, timeSource, sinks, syncLedger, syncDate, syncOptions{timezone: timezone, skipSynced: true})
+}
+
+func configOrDefault(key string, defaultValue string) string {
+	if value := config.Get(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// unattendedInputController rejects all input requests, as there is no user to answer them
+type unattendedInputController struct{}
+
+func (unattendedInputController) requestTextInput(string) (string, error) {
+	return "", fmt.Errorf("no user input available while running unattended (sync the date manually to provide it)")
+}
+
+func (unattendedInputController) requestPassword(string) (string, error) {
+	return "", fmt.Errorf("no user input available while running unattended (sync the date manually to provide it)")
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaemonCmd_CatchUpAndRunOnSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// 2020-05-26 is a Tuesday
+	clock := &FakeClock{now: time.Date(2020, 5, 26, 12, 0, 0, 0, time.Local), maxTicks: 1, cancel: cancel}
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{
+				Id:          1,
+				Duration:    240,
+				Description: "ENG-1001",
+			},
+		},
+	}
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{Records: []ledger.Record{{Date: "2020-05-21", Complete: true}}}
+
+	setupBasicConfig()
+
+	cmd := NewDaemonCmd(&MockConfigManager{InitOk: true}, togglSources(togglAPI), jiraSinks(jiraAPI), syncLedger, clock)
+	cmd.SetArgs([]string{"--schedule", "every weekday at 18:00", "--catch-up-days", "7"})
+	err := cmd.ExecuteContext(ctx)
+	assert.Nil(t, err)
+
+	assert.Len(t, jiraAPI.LoggedWork, 5, "Missed weekdays (19th, 20th, 22nd, 25th) and the scheduled one (26th) should be synced")
+	for _, date := range []string{"2020-05-19", "2020-05-20", "2020-05-21", "2020-05-22", "2020-05-25", "2020-05-26"} {
+		record, _ := syncLedger.Get(date)
+		if assert.NotNil(t, record, "Date [%s] should be in the ledger", date) {
+			assert.True(t, record.Complete)
+		}
+	}
+}
+
+func TestDaemonCmd_OverheadWorkWithoutMappingIsNotRequested(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := &FakeClock{now: time.Date(2020, 5, 22, 19, 0, 0, 0, time.Local), maxTicks: 0, cancel: cancel}
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{
+				Id:          1,
+				Pid:         1,
+				Duration:    120,
+				Description: "Writing toggl-sync tests",
+			},
+		},
+		Project: api.Project{Data: api.ProjectData{Id: 1, Name: "testing"}},
+	}
+	syncLedger := &MockLedger{}
+
+	setupBasicConfig()
+	config.Set(config.DaemonSchedule, "0 18 * * *")
+	config.Set(config.DaemonCatchUpDays, 1)
+
+	cmd := NewDaemonCmd(&MockConfigManager{InitOk: true}, togglSources(togglAPI), jiraSinks(&RejectAllCallsJiraAPI{t: t}), syncLedger, clock)
+	cmd.SetArgs([]string{})
+	err := cmd.ExecuteContext(ctx)
+	assert.Nil(t, err)
+
+	record, _ := syncLedger.Get("2020-05-22")
+	assert.False(t, record.Complete, "Entries without overhead mapping should remain pending")
+}
+
+func TestDaemonCmd_DatesInUserTimezone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// 2020-05-21 18:00 UTC is already 2020-05-22 in Kiritimati (UTC+14)
+	clock := &FakeClock{now: time.Date(2020, 5, 22, 12, 0, 0, 0, time.UTC), maxTicks: 0, cancel: cancel}
+	togglAPI := &MockTogglAPI{TimeEntries: []api.TimeEntry{{Id: 1, Duration: 240, Description: "ENG-1001"}}}
+	syncLedger := &MockLedger{}
+
+	setupBasicConfig()
+	config.Set(config.SyncTimezone, "Pacific/Kiritimati")
+
+	cmd := NewDaemonCmd(&MockConfigManager{InitOk: true}, togglSources(togglAPI), jiraSinks(&MockJiraAPI{}), syncLedger, clock)
+	cmd.SetArgs([]string{"--schedule", "0 18 * * *", "--catch-up-days", "1"})
+	err := cmd.ExecuteContext(ctx)
+	assert.Nil(t, err)
+
+	record, _ := syncLedger.Get("2020-05-22")
+	assert.NotNil(t, record, "Activation should be synced on its date in the user time zone")
+	record, _ = syncLedger.Get("2020-05-21")
+	assert.Nil(t, record)
+}
+
+func TestDaemonCmd_InvalidSchedule(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewDaemonCmd(&MockConfigManager{InitOk: true}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{}, &FakeClock{})
+	cmd.SetArgs([]string{"--schedule", "whenever I feel like it"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestDaemonCmd_ErrorInitialisingConfig(t *testing.T) {
+	cmd := NewDaemonCmd(&MockConfigManager{InitOk: false}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{}, &FakeClock{})
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+type FakeClock struct {
+	now      time.Time
+	ticks    int
+	maxTicks int
+	cancel   func()
+}
+
+func (clock *FakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *FakeClock) After(d time.Duration) <-chan time.Time {
+	if clock.ticks == clock.maxTicks {
+		clock.cancel()
+		return make(chan time.Time)
+	}
+	clock.ticks++
+	clock.now = clock.now.Add(d)
+	c := make(chan time.Time, 1)
+	c <- clock.now
+	return c
+}
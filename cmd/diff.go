@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+// Available diff statuses
+const (
+	diffOK       = "ok"
+	diffMissing  = "missing"
+	diffExtra    = "extra"
+	diffMismatch = "mismatch"
+)
+
+// NewDiffCmd creates a new Cobra Command that compares the time tracked on a date with the work logged on Jira
+func NewDiffCmd(configManager config.Manager, sources map[string]source.TimeSource, jiraAPI api.JiraAPI) *cobra.Command {
+	var sourceNames []string
+	cmd := &cobra.Command{
+		Use:   "diff <date>",
+		Short: "Compare the time tracked on a date with the work logged on Jira",
+		Long: "Compare the time tracked on a date with the work logged on Jira by the current user, per ticket. " +
+			"Tickets with time missing on Jira, extra time on Jira or mismatched durations are flagged.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := time.Parse("2006-01-02", args[0]); err != nil {
+				return fmt.Errorf("error parsing input date: %s", err)
+			}
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+			sourceNames = configuredSources(sourceNames)
+			if err := validateConfig(sourceNames); err != nil {
+				return err
+			}
+			timeSource, err := selectSources(sources, sourceNames)
+			if err != nil {
+				return err
+			}
+			location, err := userLocation(timeSource, timezoneFlag(cmd))
+			if err != nil {
+				return err
+			}
+			day, _ := time.ParseInLocation("2006-01-02", args[0], location)
+
+			tracked, err := trackedPerTicket(timeSource, location, args[0])
+			if err != nil {
+				return err
+			}
+			worklogs, err := jiraAPI.GetWorklogs(day)
+			if err != nil {
+				return fmt.Errorf("error retrieving Jira worklogs: %s", err)
+			}
+			logged := make(map[string]int)
+			for _, worklog := range worklogs {
+				logged[worklog.Ticket] += worklog.TimeSpentSeconds
+			}
+
+			return printDiff(cmd.OutOrStdout(), diffTickets(tracked, logged))
+		},
+	}
+	cmd.Flags().StringSliceVar(&sourceNames, "source", nil, "time entry source(s) to compare: toggl, clockify, csv or ics (defaults to the ones in config, or toggl)")
+	return cmd
+}
+
+type ticketDiff struct {
+	Ticket  string
+	Tracked int
+	Logged  int
+	Status  string
+}
+
+// trackedPerTicket adds up the time tracked on the date per ticket, resolving tickets like the sync does
+func trackedPerTicket(timeSource source.TimeSource, location *time.Location, date string) (map[string]int, error) {
+	entries, err := getTimeEntriesForDate(timeSource, date, location)
+	if err != nil {
+		return nil, err
+	}
+	projects := newProjectCache()
+	entries, _, err = excludeEntries(timeSource, projects, entries, entryFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, err := summarizeStrategy("")
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]int)
+	for _, entry := range summarize(stoppedEntries(entries), strategy, location) {
+		ticket, err := classifyEntry(timeSource, projects, entry, groupByTicket)
+		if err != nil {
+			return nil, err
+		}
+		tracked[ticket] += entry.Duration
+	}
+	return tracked, nil
+}
+
+func diffTickets(tracked map[string]int, logged map[string]int) []ticketDiff {
+	var diffs []ticketDiff
+	for ticket, seconds := range tracked {
+		diffs = append(diffs, ticketDiff{Ticket: ticket, Tracked: seconds, Logged: logged[ticket]})
+	}
+	for ticket, seconds := range logged {
+		if _, ok := tracked[ticket]; !ok {
+			diffs = append(diffs, ticketDiff{Ticket: ticket, Logged: seconds})
+		}
+	}
+
+	for i := range diffs {
+		switch {
+		case diffs[i].Tracked == diffs[i].Logged:
+			diffs[i].Status = diffOK
+		case diffs[i].Logged == 0:
+			diffs[i].Status = diffMissing
+		case diffs[i].Tracked == 0:
+			diffs[i].Status = diffExtra
+		default:
+			diffs[i].Status = diffMismatch
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Ticket < diffs[j].Ticket
+	})
+	return diffs
+}
+
+func printDiff(out io.Writer, diffs []ticketDiff) error {
+	discrepancies := 0
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TICKET\tTRACKED\tLOGGED\tDIFF\tSTATUS")
+	for _, diff := range diffs {
+		if diff.Status != diffOK {
+			discrepancies++
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", diff.Ticket, seconds(diff.Tracked), seconds(diff.Logged), signedSeconds(diff.Tracked-diff.Logged), diff.Status)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if discrepancies == 0 {
+		_, err := fmt.Fprintln(out, "No discrepancies found")
+		return err
+	}
+	_, err := fmt.Fprintf(out, "%d discrepancies found\n", discrepancies)
+	return err
+}
+
+func seconds(value int) string {
+	return (time.Duration(value) * time.Second).String()
+}
+
+func signedSeconds(value int) string {
+	if value > 0 {
+		return "+" + seconds(value)
+	}
+	return seconds(value)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCmd(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Duration: 1800, Description: "ENG-1001"},
+			{Id: 2, Duration: 1800, Description: "ENG-1001"},
+			{Id: 3, Duration: 600, Description: "ENG-1002"},
+			{Id: 4, Pid: 7, Duration: 900, Description: "Team catch-up"},
+			{Id: 5, Duration: -1590141600, Description: "ENG-1004"},
+		},
+		Project: api.Project{Data: api.ProjectData{Id: 7, Name: "Meetings"}},
+	}
+	jiraAPI := &MockJiraAPI{
+		Worklogs: []api.JiraWorklog{
+			{ID: "1", Ticket: "ENG-1001", TimeSpentSeconds: 3600},
+			{ID: "2", Ticket: "MGMT-1", TimeSpentSeconds: 600},
+			{ID: "3", Ticket: "ENG-1003", TimeSpentSeconds: 1200},
+		},
+	}
+	output := bytes.NewBufferString("")
+
+	cmd := NewDiffCmd(&MockConfigManager{InitOk: true}, togglSources(togglAPI), jiraAPI)
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"TICKET    TRACKED  LOGGED  DIFF    STATUS\n"+
+		"ENG-1001  1h0m0s   1h0m0s  0s      ok\n"+
+		"ENG-1002  10m0s    0s      +10m0s  missing\n"+
+		"ENG-1003  0s       20m0s   -20m0s  extra\n"+
+		"MGMT-1    15m0s    10m0s   +5m0s   mismatch\n"+
+		"3 discrepancies found\n", output.String())
+}
+
+func TestDiffCmd_NoDiscrepancies(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{TimeEntries: []api.TimeEntry{{Id: 1, Duration: 3600, Description: "ENG-1001"}}}
+	jiraAPI := &MockJiraAPI{Worklogs: []api.JiraWorklog{{ID: "1", Ticket: "ENG-1001", TimeSpentSeconds: 3600}}}
+	output := bytes.NewBufferString("")
+
+	cmd := NewDiffCmd(&MockConfigManager{InitOk: true}, togglSources(togglAPI), jiraAPI)
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "No discrepancies found")
+}
+
+func TestDiffCmd_JiraError(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewDiffCmd(&MockConfigManager{InitOk: true}, togglSources(&MockTogglAPI{}), &MockJiraAPI{APIError: errors.New("stub error")})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
+
+func TestDiffCmd_InvalidDate(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewDiffCmd(&MockConfigManager{InitOk: true}, togglSources(&MockTogglAPI{}), &RejectAllCallsJiraAPI{t: t})
+	cmd.SetArgs([]string{"22/05/2020"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCmd creates a new Cobra Command that checks the configuration, credentials and connectivity
+func NewDoctorCmd(configManager config.Manager, sources map[string]source.TimeSource, jiraAPIForInstance func(instance string) api.JiraAPI) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the configuration, credentials and connectivity",
+		Long: "Check the configuration, credentials and connectivity: required config values, " +
+			"time source and Jira credentials, Jira project keys, overhead tickets and config file permissions.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			doctor := &doctorReport{out: cmd.OutOrStdout()}
+			doctor.checkConfigFile()
+			sourceNames := configuredSources(nil)
+			if doctor.checkRequiredConfig(sourceNames) {
+				doctor.checkSources(sources, sourceNames)
+				doctor.checkJira(jiraAPIForInstance)
+			}
+
+			if doctor.failures != 0 {
+				return fmt.Errorf("%d problem(s) found", doctor.failures)
+			}
+			return nil
+		},
+	}
+}
+
+type doctorReport struct {
+	out      io.Writer
+	failures int
+}
+
+func (doctor *doctorReport) ok(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(doctor.out, "[OK]   "+format+"\n", args...)
+}
+
+func (doctor *doctorReport) warn(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(doctor.out, "[WARN] "+format+"\n", args...)
+}
+
+func (doctor *doctorReport) fail(format string, args ...interface{}) {
+	doctor.failures++
+	_, _ = fmt.Fprintf(doctor.out, "[FAIL] "+format+"\n", args...)
+}
+
+// checkConfigFile warns when the config file (which contains credentials) is accessible by other users
+func (doctor *doctorReport) checkConfigFile() {
+	path := config.FileUsed()
+	info, err := os.Stat(path)
+	if err != nil {
+		doctor.warn("Config file [%s] could not be inspected: %s", path, err)
+	} else if perm := info.Mode().Perm(); perm&0077 != 0 {
+		doctor.warn("Config file [%s] is accessible by other users (mode %04o); run 'chmod 600 %s'", path, perm, path)
+	} else {
+		doctor.ok("Config file [%s] is only accessible by its owner", path)
+	}
+}
+
+func (doctor *doctorReport) checkRequiredConfig(sourceNames []string) bool {
+	missing := missingConfig(sourceNames)
+	for _, key := range missing {
+		doctor.fail("Missing config value [%s]", key)
+	}
+	if len(missing) == 0 {
+		doctor.ok("All required config values are set")
+	}
+	return len(missing) == 0
+}
+
+func (doctor *doctorReport) checkSources(sources map[string]source.TimeSource, sourceNames []string) {
+	for _, name := range sourceNames {
+		provider, ok := sources[name].(source.UserDetailsProvider)
+		if !ok {
+			continue
+		}
+		me, err := provider.GetMe()
+		if err != nil {
+			doctor.fail("Credentials of source [%s] were rejected: %s", name, err)
+		} else {
+			doctor.ok("Credentials of source [%s] belong to %s (%s)", name, me.Data.Fullname, me.Data.Email)
+		}
+	}
+}
+
+// checkJira verifies the credentials of every Jira instance, their project keys, and the tickets used for overhead work
+func (doctor *doctorReport) checkJira(jiraAPIForInstance func(instance string) api.JiraAPI) {
+	if !contains(configuredSinks(), sink.Jira) {
+		return
+	}
+
+	available := make(map[string]api.JiraAPI)
+	for _, instance := range append([]string{""}, config.GetJiraInstances()...) {
+		jiraAPI := jiraAPIForInstance(instance)
+		name, projectKey := "default", config.JiraProjectKey
+		if instance != "" {
+			name, projectKey = instance, config.JiraInstanceKey(instance, config.JiraProjectKey)
+		}
+
+		me, err := jiraAPI.GetMyself()
+		if err != nil {
+			doctor.fail("Credentials of Jira instance [%s] were rejected: %s", name, err)
+			continue
+		}
+		doctor.ok("Credentials of Jira instance [%s] belong to %s", name, me.DisplayName)
+		available[instance] = jiraAPI
+
+		for _, key := range config.GetSlice(projectKey) {
+			exists, err := jiraAPI.ProjectExists(key)
+			if err != nil {
+				doctor.fail("Jira project [%s] could not be checked: %s", key, err)
+			} else if !exists {
+				doctor.fail("Jira project [%s] does not exist on instance [%s]", key, name)
+			} else {
+				doctor.ok("Jira project [%s] exists", key)
+			}
+		}
+	}
+
+	mappings := config.GetOverheadMappings()
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return strings.ToLower(mappings[i].Project) < strings.ToLower(mappings[j].Project)
+	})
+	for _, mapping := range mappings {
+		ticket, project := mapping.Ticket, mapping.Project
+		jiraAPI, ok := available[config.GetJiraInstanceForTicket(ticket)]
+		if !ok {
+			continue
+		}
+
+		exists, err := jiraAPI.TicketExists(ticket)
+		if err != nil {
+			doctor.fail("Overhead ticket [%s] (project [%s]) could not be checked: %s", ticket, project, err)
+		} else if !exists {
+			doctor.fail("Overhead ticket [%s] (project [%s]) does not exist", ticket, project)
+		} else {
+			doctor.ok("Overhead ticket [%s] (project [%s]) exists", ticket, project)
+		}
+	}
+}
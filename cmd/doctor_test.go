@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctorCmd_Healthy(t *testing.T) {
+	setupDoctorConfig(t, 0600)
+	togglAPI := &MockTogglAPI{Me: api.Me{Data: api.PersonalInfo{Email: "tester@toggl-sync.com", Fullname: "TogglSync Tester"}}}
+	jiraAPI := &MockJiraAPI{Myself: api.JiraUser{DisplayName: "TogglSync Tester"}}
+	output := bytes.NewBufferString("")
+
+	cmd := NewDoctorCmd(&MockConfigManager{InitOk: true}, togglSources(togglAPI), jiraAPIs(jiraAPI))
+	cmd.SetOut(output)
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"[OK]   Config file ["+config.FileUsed()+"] is only accessible by its owner\n"+
+		"[OK]   All required config values are set\n"+
+		"[OK]   Credentials of source [toggl] belong to TogglSync Tester (tester@toggl-sync.com)\n"+
+		"[OK]   Credentials of Jira instance [default] belong to TogglSync Tester\n"+
+		"[OK]   Jira project [ENG] exists\n"+
+		"[OK]   Jira project [MGMT] exists\n"+
+		"[OK]   Overhead ticket [MGMT-1] (project [Meetings]) exists\n",
+		output.String())
+}
+
+func TestDoctorCmd_Problems(t *testing.T) {
+	setupDoctorConfig(t, 0644)
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Hiring", Ticket: "MGMT-404"})
+	jiraAPI := &MockJiraAPI{UnknownKeys: []string{"MGMT", "MGMT-404"}}
+	output := bytes.NewBufferString("")
+
+	cmd := NewDoctorCmd(&MockConfigManager{InitOk: true}, togglSources(&MockTogglAPI{MeError: errors.New("stub error")}), jiraAPIs(jiraAPI))
+	cmd.SetOut(output)
+	err := cmd.Execute()
+
+	assert.EqualError(t, err, "3 problem(s) found")
+	assert.Contains(t, output.String(), "[WARN] Config file ["+config.FileUsed()+"] is accessible by other users (mode 0644)")
+	assert.Contains(t, output.String(), "[FAIL] Credentials of source [toggl] were rejected: stub error\n")
+	assert.Contains(t, output.String(), "[FAIL] Jira project [MGMT] does not exist on instance [default]\n")
+	assert.Contains(t, output.String(), "[FAIL] Overhead ticket [MGMT-404] (project [Hiring]) does not exist\n")
+	assert.Contains(t, output.String(), "[OK]   Overhead ticket [MGMT-1] (project [Meetings]) exists\n")
+}
+
+func TestDoctorCmd_MissingConfigValues(t *testing.T) {
+	setupDoctorConfig(t, 0600)
+	config.Set(config.JiraPassword, "")
+	config.Set(config.TogglUsername, "")
+	output := bytes.NewBufferString("")
+
+	cmd := NewDoctorCmd(&MockConfigManager{InitOk: true}, togglSources(&MockTogglAPI{}), jiraAPIs(&RejectAllCallsJiraAPI{t: t}))
+	cmd.SetOut(output)
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+	assert.Contains(t, output.String(), "[FAIL] Missing config value [toggl.username]\n")
+	assert.Contains(t, output.String(), "[FAIL] Missing config value [jira.password]\n")
+}
+
+func setupDoctorConfig(t *testing.T, perm os.FileMode) {
+	setupBasicConfig()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte{}, perm))
+	assert.Nil(t, os.Chmod(path, perm))
+	viper.SetConfigFile(path)
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+}
+
+func jiraAPIs(jiraAPI api.JiraAPI) func(string) api.JiraAPI {
+	return func(string) api.JiraAPI {
+		return jiraAPI
+	}
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/source"
+)
+
+// exclusionRules leave entries out of the sync (e.g. lunch, personal errands or entries tagged "no-sync")
+type exclusionRules struct {
+	tags        []string
+	projects    []string
+	description *regexp.Regexp
+	billable    *bool
+	clients     []string
+}
+
+// entryFilters narrow down the entries of a single sync (see --billable-only and --client)
+type entryFilters struct {
+	billableOnly bool
+	clients      []string
+}
+
+// excludedEntry is an entry left out of the sync, along with the rule that excluded it
+type excludedEntry struct {
+	api.TimeEntry
+	Reason string
+}
+
+// loadExclusionRules reads the rules from config (tags, project names or IDs, a description pattern and a billable flag),
+// adding those of the filters provided
+func loadExclusionRules(filters entryFilters) (*exclusionRules, error) {
+	rules := &exclusionRules{
+		tags:     config.GetSlice(config.SyncExcludeTags),
+		projects: config.GetSlice(config.SyncExcludeProjects),
+		clients:  filters.clients,
+	}
+	if pattern := config.Get(config.SyncExcludeDescription); pattern != "" {
+		description, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for [%s]: %s", config.SyncExcludeDescription, err)
+		}
+		rules.description = description
+	}
+	if value := config.Get(config.SyncExcludeBillable); value != "" {
+		billable, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for [%s]: [%s] is not true or false", config.SyncExcludeBillable, value)
+		}
+		rules.billable = &billable
+	}
+	if filters.billableOnly {
+		billable := false
+		rules.billable = &billable
+	}
+	return rules, nil
+}
+
+// projectCache keeps the projects (and client names) retrieved from the time source during a run, so each one is retrieved once
+type projectCache struct {
+	projects    map[int]*api.Project
+	clientNames map[int]string
+}
+
+func newProjectCache() *projectCache {
+	return &projectCache{projects: make(map[int]*api.Project)}
+}
+
+func (cache *projectCache) project(timeSource source.TimeSource, pid int) (*api.Project, error) {
+	if project, ok := cache.projects[pid]; ok {
+		return project, nil
+	}
+	project, err := timeSource.GetProjectById(pid)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving project information failed with an error: %s", err)
+	}
+	cache.projects[pid] = project
+	return project, nil
+}
+
+// clientName returns the name of the client (empty if the time source provides no clients), retrieving all clients at once
+func (cache *projectCache) clientName(timeSource source.TimeSource, cid int) (string, error) {
+	provider, ok := timeSource.(source.ClientProvider)
+	if !ok || cid == 0 {
+		return "", nil
+	}
+	if cache.clientNames == nil {
+		clients, err := provider.GetClients()
+		if err != nil {
+			return "", fmt.Errorf("retrieving clients failed with an error: %s", err)
+		}
+		cache.clientNames = make(map[int]string)
+		for _, client := range clients {
+			cache.clientNames[client.Id] = client.Name
+		}
+	}
+	return cache.clientNames[cid], nil
+}
+
+// withProjectDetails fills in the client and color of the project of the entry (if any)
+func (cache *projectCache) withProjectDetails(timeSource source.TimeSource, entry api.TimeEntry) (api.TimeEntry, error) {
+	if entry.Pid == 0 {
+		return entry, nil
+	}
+	project, err := cache.project(timeSource, entry.Pid)
+	if err != nil {
+		return entry, err
+	}
+	entry.Cid, entry.Client, entry.Color = project.Data.Cid, project.Data.Client, project.Data.Color
+	if entry.Client == "" {
+		entry.Client, err = cache.clientName(timeSource, entry.Cid)
+	}
+	return entry, err
+}
+
+// excludeEntries splits the entries into those to sync and those excluded by the rules in config (or the filters provided).
+// Entries are returned with the details of their project (see projectCache.withProjectDetails); those details are
+// only required when filtering by client, so failing to retrieve them does not stop the sync otherwise.
+func excludeEntries(timeSource source.TimeSource, projects *projectCache, entries []api.TimeEntry, filters entryFilters) ([]api.TimeEntry, []excludedEntry, error) {
+	rules, err := loadExclusionRules(filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var kept []api.TimeEntry
+	var excluded []excludedEntry
+	for _, entry := range entries {
+		entry, err := projects.withProjectDetails(timeSource, entry)
+		if err != nil && len(rules.clients) != 0 {
+			return nil, nil, err
+		}
+		reason, err := rules.reason(timeSource, projects, entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reason != "" {
+			excluded = append(excluded, excludedEntry{TimeEntry: entry, Reason: reason})
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, excluded, nil
+}
+
+// reason returns the rule excluding the entry (empty if none does)
+func (rules *exclusionRules) reason(timeSource source.TimeSource, projects *projectCache, entry api.TimeEntry) (string, error) {
+	for _, tag := range entry.Tags {
+		for _, excludedTag := range rules.tags {
+			if strings.EqualFold(tag, excludedTag) {
+				return fmt.Sprintf("tag [%s]", tag), nil
+			}
+		}
+	}
+
+	if len(rules.projects) != 0 && entry.Pid != 0 {
+		name, err := projectName(timeSource, projects, entry.Pid)
+		if err != nil {
+			return "", err
+		}
+		for _, project := range rules.projects {
+			if strings.EqualFold(project, name) || project == strconv.Itoa(entry.Pid) {
+				return fmt.Sprintf("project [%s]", name), nil
+			}
+		}
+	}
+
+	if rules.description != nil && rules.description.MatchString(entry.Description) {
+		return fmt.Sprintf("description matching [%s]", rules.description), nil
+	}
+
+	if rules.billable != nil && entry.Billable == *rules.billable {
+		if entry.Billable {
+			return "billable", nil
+		}
+		return "not billable", nil
+	}
+
+	if len(rules.clients) != 0 {
+		return rules.clientReason(timeSource, entry)
+	}
+	return "", nil
+}
+
+// clientReason excludes entries whose project is not done for one of the clients (names or IDs) requested.
+// Entries without a project (e.g. Jira tickets) have no client, so they are excluded too.
+func (rules *exclusionRules) clientReason(timeSource source.TimeSource, entry api.TimeEntry) (string, error) {
+	if entry.Cid == 0 {
+		return "no client", nil
+	} else if _, ok := timeSource.(source.ClientProvider); !ok && entry.Client == "" {
+		return "", fmt.Errorf("unable to filter by client; the time entry source provides no clients")
+	}
+
+	for _, client := range rules.clients {
+		if strings.EqualFold(client, entry.Client) || client == strconv.Itoa(entry.Cid) {
+			return "", nil
+		}
+	}
+	name := entry.Client
+	if name == "" {
+		name = strconv.Itoa(entry.Cid)
+	}
+	return fmt.Sprintf("client [%s]", name), nil
+}
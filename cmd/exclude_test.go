@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCmd_ExcludedEntries(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncExcludeTags, []string{"no-sync"})
+	config.Set(config.SyncExcludeProjects, []string{"personal"})
+	config.Set(config.SyncExcludeDescription, "(?i)^lunch")
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Duration: 240, Description: "ENG-1001"},
+			{Id: 2, Duration: 3600, Description: "Lunch"},
+			{Id: 3, Duration: 600, Description: "Groceries", Tags: []string{"No-Sync"}},
+			{Id: 4, Pid: 9, Duration: 900, Description: "Dentist"},
+			{Id: 5, Duration: -1590000000, Description: "lunch break"},
+		},
+		Project: api.Project{Data: api.ProjectData{Id: 9, Name: "Personal"}},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestExcludeEntries(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncExcludeProjects, []string{"7"})
+	config.Set(config.SyncExcludeBillable, "false")
+	togglAPI := &MockTogglAPI{Project: api.Project{Data: api.ProjectData{Id: 7, Name: "Errands"}}}
+	entries := []api.TimeEntry{
+		{Id: 1, Duration: 240, Description: "ENG-1001", Billable: true},
+		{Id: 2, Duration: 60, Description: "ENG-1002"},
+		{Id: 3, Pid: 7, Duration: 600, Description: "Post office", Billable: true},
+	}
+
+	kept, excluded, err := excludeEntries(togglAPI, newProjectCache(), entries, entryFilters{})
+	assert.Nil(t, err)
+	assert.Equal(t, entries[:1], kept)
+	assert.Equal(t, []excludedEntry{
+		{TimeEntry: entries[1], Reason: "not billable"},
+		{TimeEntry: entries[2], Reason: "project [Errands]"},
+	}, excluded)
+}
+
+func TestExcludeEntries_InvalidPattern(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncExcludeDescription, "lunch(")
+
+	_, _, err := excludeEntries(&MockTogglAPI{}, newProjectCache(), []api.TimeEntry{{Id: 1, Description: "Lunch"}}, entryFilters{})
+	assert.NotNil(t, err)
+}
+
+func TestRootCmd_BillableOnly(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Duration: 240, Description: "ENG-1001", Billable: true},
+			{Id: 2, Duration: 60, Description: "ENG-1002"},
+		},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--billable-only"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestRootCmd_ClientFilter(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{ProjectID: 7, Project: "Website", Ticket: "ACME-12"})
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Duration: 240, Description: "ENG-1001"},
+			{Id: 2, Pid: 7, Duration: 1800, Description: "Landing page"},
+		},
+		Project: api.Project{Data: api.ProjectData{Id: 7, Name: "Website", Cid: 3}},
+		Clients: []api.ClientData{{Id: 3, Name: "ACME"}, {Id: 4, Name: "Initech"}},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--client", "acme"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	// Entries without a project have no client
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("Landing page", 1800))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestExcludeEntries_OtherClient(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{
+		Project: api.Project{Data: api.ProjectData{Id: 7, Name: "Website", Cid: 3, Color: "#06aaf5"}},
+		Clients: []api.ClientData{{Id: 3, Name: "ACME"}},
+	}
+	entries := []api.TimeEntry{{Id: 2, Pid: 7, Duration: 1800, Description: "Landing page"}}
+
+	kept, excluded, err := excludeEntries(togglAPI, newProjectCache(), entries, entryFilters{clients: []string{"Initech", "4"}})
+	assert.Nil(t, err)
+	assert.Empty(t, kept)
+	expected := entries[0]
+	expected.Cid, expected.Client, expected.Color = 3, "ACME", "#06aaf5"
+	assert.Equal(t, []excludedEntry{{TimeEntry: expected, Reason: "client [ACME]"}}, excluded)
+}
+
+func TestExcludeEntries_ClientsNotProvided(t *testing.T) {
+	setupBasicConfig()
+	entries := []api.TimeEntry{{Id: 2, Pid: 7, Duration: 1800, Description: "Landing page"}}
+
+	_, _, err := excludeEntries(clientlessSource{}, newProjectCache(), entries, entryFilters{clients: []string{"ACME"}})
+	assert.EqualError(t, err, "unable to filter by client; the time entry source provides no clients")
+}
+
+// clientlessSource is a time source whose projects belong to a client it cannot describe
+type clientlessSource struct{}
+
+func (clientlessSource) GetTimeEntries(time.Time, time.Time) ([]api.TimeEntry, error) {
+	return nil, nil
+}
+
+func (clientlessSource) GetProjectById(id int) (*api.Project, error) {
+	return &api.Project{Data: api.ProjectData{Id: id, Name: "Website", Cid: 3}}, nil
+}
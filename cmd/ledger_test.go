@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+var ledgerTestEntries = []api.TimeEntry{
+	{
+		Id:          1,
+		Duration:    240,
+		Description: "ENG-1001",
+	},
+	{
+		Id:          2,
+		Duration:    120,
+		Description: "ENG-1002",
+	},
+}
+
+func TestRootCmd_RecordsSyncInLedger(t *testing.T) {
+	syncLedger := &MockLedger{}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{TimeEntries: ledgerTestEntries}), jiraSinks(&MockJiraAPI{}), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	record, _ := syncLedger.Get("2020-05-22")
+	assert.True(t, record.Complete)
+	assert.ElementsMatch(t, []ledger.Worklog{
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}},
+		{Sink: "jira", Ticket: "ENG-1002", Description: "ENG-1002", Seconds: 120, EntryIDs: []int{2}},
+	}, record.Worklogs)
+}
+
+func TestRootCmd_FailedSyncIsIncompleteInLedger(t *testing.T) {
+	syncLedger := &MockLedger{}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{TimeEntries: ledgerTestEntries}), jiraSinks(&MockJiraAPI{APIError: errors.New("stub error")}), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	record, _ := syncLedger.Get("2020-05-22")
+	assert.False(t, record.Complete)
+	assert.Empty(t, record.Worklogs)
+}
+
+func TestRootCmd_SkipDateAlreadySynced(t *testing.T) {
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     "2020-05-22",
+		Complete: true,
+		Worklogs: []ledger.Worklog{
+			{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}},
+			{Sink: "jira", Ticket: "ENG-1002", Description: "ENG-1002", Seconds: 120, EntryIDs: []int{2}},
+		},
+	}}}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{TimeEntries: ledgerTestEntries}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+}
+
+func TestRootCmd_SyncTimeTrackedSinceDateWasSynced(t *testing.T) {
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     "2020-05-22",
+		Complete: true,
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 180, EntryIDs: []int{1}}},
+	}}}
+	jiraAPI := &MockJiraAPI{}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{TimeEntries: ledgerTestEntries}), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	// Only the time added to the entry (and the new entry) are logged
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("ENG-1001", 60))
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("ENG-1002", 120))
+	assert.NoError(t, jiraAPI.VerifyNoOtherWorkLogged())
+	record, _ := syncLedger.Get("2020-05-22")
+	assert.True(t, record.Complete)
+	assert.ElementsMatch(t, []ledger.Worklog{
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 180, EntryIDs: []int{1}},
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 60, EntryIDs: []int{1}},
+		{Sink: "jira", Ticket: "ENG-1002", Description: "ENG-1002", Seconds: 120, EntryIDs: []int{2}},
+	}, record.Worklogs)
+}
+
+func TestRootCmd_DryRunDateAlreadySynced(t *testing.T) {
+	syncLedger := &MockLedger{Records: []ledger.Record{{Date: "2020-05-22", Complete: true}}}
+	togglAPI := &MockTogglAPI{TimeEntries: ledgerTestEntries}
+
+	setupBasicConfig()
+
+	output := captureLog(func() {
+		cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(&RejectAllCallsJiraAPI{t: t}), syncLedger)
+		cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
+		assert.Nil(t, cmd.Execute())
+	})
+
+	assert.Contains(t, output, "Entry: ENG-1001 || Duration (s): 240")
+	assert.Contains(t, output, "Entry: ENG-1002 || Duration (s): 120")
+}
+
+func TestRootCmd_SkipWorklogsAlreadySynced(t *testing.T) {
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     "2020-05-22",
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240}},
+	}}}
+	jiraAPI := &MockJiraAPI{}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{TimeEntries: ledgerTestEntries}), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("ENG-1002", 120))
+	assert.NoError(t, jiraAPI.VerifyNoOtherWorkLogged())
+	record, _ := syncLedger.Get("2020-05-22")
+	assert.True(t, record.Complete)
+	assert.Len(t, record.Worklogs, 2)
+}
+
+func TestRootCmd_ForceSyncDateAlreadySynced(t *testing.T) {
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     "2020-05-22",
+		Complete: true,
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240}},
+	}}}
+	jiraAPI := &MockJiraAPI{}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{TimeEntries: ledgerTestEntries}), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22", "--force"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("ENG-1002", 120))
+	assert.NoError(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestRootCmd_ErrorSavingLedger(t *testing.T) {
+	syncLedger := &MockLedger{SaveError: errors.New("stub error")}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{TimeEntries: ledgerTestEntries}), jiraSinks(&MockJiraAPI{}), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/spf13/cobra"
+)
+
+// Available overhead mapping statuses
+const (
+	overheadMapped   = "mapped"
+	overheadUnmapped = "unmapped"
+	overheadUnknown  = "unknown project"
+)
+
+// NewOverheadCmd creates a new Cobra Command that helps managing the Jira tickets used for overhead projects
+func NewOverheadCmd(configManager config.Manager, togglAPI api.TogglAPI) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "overhead",
+		Short: "Manage the Jira tickets used for overhead projects",
+		Long: "Manage the Jira tickets used for overhead projects (i.e. time entries not referencing a Jira ticket), " +
+			"so mappings can be set up ahead of time",
+	}
+	cmd.AddCommand(newOverheadListCmd(configManager, togglAPI))
+	cmd.AddCommand(newOverheadAddCmd(configManager, togglAPI))
+	cmd.AddCommand(newOverheadRemoveCmd(configManager))
+	cmd.AddCommand(newOverheadRenameCmd(configManager))
+	cmd.AddCommand(newOverheadImportCmd(configManager))
+	cmd.AddCommand(newOverheadExportCmd(configManager))
+	return cmd
+}
+
+func newOverheadListCmd(configManager config.Manager, togglAPI api.TogglAPI) *cobra.Command {
+	var unmappedOnly bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all Toggl projects along with their overhead tickets",
+		Long: "List all Toggl projects along with their overhead tickets, highlighting the ones without a ticket. " +
+			"Mappings of projects not found in Toggl are listed too.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			projects, err := togglAPI.GetProjects()
+			if err != nil {
+				return fmt.Errorf("retrieving projects failed with an error: %s", err)
+			}
+			rows := overheadRows(projects)
+			if unmappedOnly {
+				var unmapped []overheadRow
+				for _, row := range rows {
+					if row.Status == overheadUnmapped {
+						unmapped = append(unmapped, row)
+					}
+				}
+				rows = unmapped
+			}
+			return printOverheadRows(cmd.OutOrStdout(), rows)
+		},
+	}
+	cmd.Flags().BoolVar(&unmappedOnly, "unmapped", false, "only list projects without an overhead ticket")
+	return cmd
+}
+
+func newOverheadAddCmd(configManager config.Manager, togglAPI api.TogglAPI) *cobra.Command {
+	var projectID int
+	cmd := &cobra.Command{
+		Use:   "add <project> <ticket>",
+		Short: "Map a project to an overhead ticket",
+		Long: "Map a project to the Jira ticket its entries are logged on (replacing the existing ticket, if any). " +
+			"The project ID is looked up on Toggl, unless --project-id is provided.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			mapping := config.OverheadMapping{ProjectID: projectID, Project: args[0], Ticket: args[1]}
+			if err := validateOverheadTicket(mapping.Project, mapping.Ticket); err != nil {
+				return err
+			}
+			if mapping.ProjectID == 0 {
+				projects, err := togglAPI.GetProjects()
+				if err != nil {
+					return fmt.Errorf("retrieving projects failed with an error: %s", err)
+				}
+				for _, project := range projects {
+					if strings.EqualFold(project.Name, mapping.Project) {
+						mapping.ProjectID, mapping.Project = project.Id, project.Name
+					}
+				}
+				if mapping.ProjectID == 0 {
+					log.Printf("Project [%s] not found on Toggl; its entries will be matched by name", mapping.Project)
+				}
+			}
+			config.SetOverheadMapping(mapping)
+			return persistConfig(configManager)
+		},
+	}
+	cmd.Flags().IntVar(&projectID, "project-id", 0, "ID of the project on Toggl")
+	return cmd
+}
+
+func newOverheadRemoveCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <project>",
+		Short: "Remove the overhead ticket of a project",
+		Long:  "Remove the overhead ticket of a project (toggl-sync asks for a new one the next time the project is synced)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			if !config.RemoveOverheadMapping(0, args[0]) {
+				return fmt.Errorf("project [%s] has no overhead ticket assigned", args[0])
+			}
+			return persistConfig(configManager)
+		},
+	}
+}
+
+func newOverheadRenameCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <project> <new-project>",
+		Short: "Move the overhead ticket of a project to a new project name",
+		Long:  "Move the overhead ticket of a project to a new project name (e.g. after renaming the project on Toggl)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			project, newProject := args[0], args[1]
+			mapping, ok := config.FindOverheadMapping(0, project)
+			if !ok {
+				return fmt.Errorf("project [%s] has no overhead ticket assigned", project)
+			}
+			if existing, ok := config.FindOverheadMapping(0, newProject); ok && !strings.EqualFold(project, newProject) {
+				return fmt.Errorf("project [%s] already has an overhead ticket assigned (%s)", newProject, existing.Ticket)
+			}
+			config.RemoveOverheadMapping(0, project)
+			mapping.Project = newProject
+			config.SetOverheadMapping(mapping)
+			return persistConfig(configManager)
+		},
+	}
+}
+
+func newOverheadImportCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import overhead tickets from a CSV file",
+		Long: "Import overhead tickets from a CSV file with 'project_id,project,ticket' rows (as written by 'overhead export'). " +
+			"Existing mappings of the same projects are replaced; nothing is imported if any row is invalid.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("error opening file: %s", err)
+			}
+			defer f.Close()
+
+			mappings, err := readOverheadMappings(f)
+			if err != nil {
+				return err
+			}
+			for _, mapping := range mappings {
+				config.SetOverheadMapping(mapping)
+			}
+			if err := persistConfig(configManager); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%d overhead ticket(s) imported\n", len(mappings))
+			return err
+		},
+	}
+}
+
+func newOverheadExportCmd(configManager config.Manager) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export overhead tickets as CSV",
+		Long:  "Export overhead tickets as CSV, with 'project_id,project,ticket' rows (to stdout, unless --output is provided)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("error creating file: %s", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			return writeOverheadMappings(out, sortedOverheadMappings())
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write the CSV to")
+	return cmd
+}
+
+type overheadRow struct {
+	config.OverheadMapping
+	Status string
+}
+
+// overheadRows lists the Toggl projects along with their tickets, followed by the mappings of any other project
+func overheadRows(projects []api.ProjectData) []overheadRow {
+	var rows []overheadRow
+	used := make(map[config.OverheadMapping]bool)
+	for _, project := range projects {
+		row := overheadRow{OverheadMapping: config.OverheadMapping{ProjectID: project.Id, Project: project.Name}, Status: overheadUnmapped}
+		if mapping, ok := config.FindOverheadMapping(project.Id, project.Name); ok {
+			used[mapping] = true
+			row.Ticket, row.Status, row.Origin = mapping.Ticket, overheadMapped, mapping.Origin
+		}
+		rows = append(rows, row)
+	}
+	for _, mapping := range config.GetOverheadMappings() {
+		if !used[mapping] {
+			rows = append(rows, overheadRow{OverheadMapping: mapping, Status: overheadUnknown})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return strings.ToLower(rows[i].Project) < strings.ToLower(rows[j].Project)
+	})
+	return rows
+}
+
+func sortedOverheadMappings() []config.OverheadMapping {
+	mappings := config.GetOverheadMappings()
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return strings.ToLower(mappings[i].Project) < strings.ToLower(mappings[j].Project)
+	})
+	return mappings
+}
+
+func printOverheadRows(out io.Writer, rows []overheadRow) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "ID\tPROJECT\tTICKET\tSTATUS")
+	unmapped := 0
+	for _, row := range rows {
+		id, ticket := "-", row.Ticket
+		if row.ProjectID != 0 {
+			id = strconv.Itoa(row.ProjectID)
+		}
+		if row.Status == overheadUnmapped {
+			ticket = "-"
+			unmapped++
+		}
+		status := row.Status
+		if row.Origin == config.OriginTeam {
+			status += " (team)"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", id, row.Project, ticket, status)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	if unmapped != 0 {
+		_, err := fmt.Fprintf(out, "%d project(s) without overhead ticket\n", unmapped)
+		return err
+	}
+	return nil
+}
+
+func readOverheadMappings(in io.Reader) ([]config.OverheadMapping, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV: %s", err)
+	}
+
+	var mappings []config.OverheadMapping
+	for i, row := range rows {
+		if i == 0 && strings.EqualFold(row[0], "project_id") {
+			continue
+		}
+		mapping := config.OverheadMapping{Project: row[1], Ticket: row[2]}
+		if row[0] != "" {
+			if mapping.ProjectID, err = strconv.Atoi(row[0]); err != nil {
+				return nil, fmt.Errorf("line %d: [%s] is not a valid project ID", i+1, row[0])
+			}
+		}
+		if err := validateOverheadTicket(mapping.Project, mapping.Ticket); err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+func writeOverheadMappings(out io.Writer, mappings []config.OverheadMapping) error {
+	writer := csv.NewWriter(out)
+	_ = writer.Write([]string{"project_id", "project", "ticket"})
+	for _, mapping := range mappings {
+		id := ""
+		if mapping.ProjectID != 0 {
+			id = strconv.Itoa(mapping.ProjectID)
+		}
+		_ = writer.Write([]string{id, mapping.Project, mapping.Ticket})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func validateOverheadTicket(project string, ticket string) error {
+	if project == "" {
+		return fmt.Errorf("project name cannot be empty")
+	}
+	if !jiraTicketFormat.MatchString(ticket) {
+		return fmt.Errorf("[%s] is not a valid Jira ticket (e.g. ENG-123)", ticket)
+	}
+	return nil
+}
+
+func persistConfig(configManager config.Manager) error {
+	if err := configManager.Persist(); err != nil {
+		return fmt.Errorf("error saving configuration to file: %s", err)
+	}
+	return nil
+}
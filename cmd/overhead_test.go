@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverheadListCmd(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{ProjectID: 7, Project: "Team meetings", Ticket: "MGMT-1"})
+	config.SetOverheadMapping(config.OverheadMapping{ProjectID: 3, Project: "Archived", Ticket: "MGMT-9"})
+	togglAPI := &MockTogglAPI{Projects: []api.ProjectData{{Id: 8, Name: "Recruitment"}, {Id: 7, Name: "Meetings"}}}
+	output := bytes.NewBufferString("")
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, togglAPI)
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"list"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"ID  PROJECT      TICKET  STATUS\n"+
+		"3   Archived     MGMT-9  unknown project\n"+
+		"7   Meetings     MGMT-1  mapped\n"+
+		"8   Recruitment  -       unmapped\n"+
+		"1 project(s) without overhead ticket\n",
+		output.String())
+}
+
+func TestOverheadListCmd_UnmappedOnly(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+	togglAPI := &MockTogglAPI{Projects: []api.ProjectData{{Id: 8, Name: "Recruitment"}, {Id: 7, Name: "Meetings"}}}
+	output := bytes.NewBufferString("")
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, togglAPI)
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"list", "--unmapped"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"ID  PROJECT      TICKET  STATUS\n"+
+		"8   Recruitment  -       unmapped\n"+
+		"1 project(s) without overhead ticket\n",
+		output.String())
+}
+
+func TestOverheadListCmd_TogglError(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{ProjectError: errors.New("stub error")})
+	cmd.SetArgs([]string{"list"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
+
+func TestOverheadAddCmd(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetArgs([]string{"add", "Meetings", "MGMT-1"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "MGMT-1", config.GetOverheadTicket(0, "Meetings"))
+}
+
+func TestOverheadAddCmd_ProjectIDFromToggl(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{Projects: []api.ProjectData{{Id: 7, Name: "Ops.Support"}}}
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, togglAPI)
+	cmd.SetArgs([]string{"add", "ops.support", "OPS-1"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []config.OverheadMapping{{ProjectID: 7, Project: "Ops.Support", Ticket: "OPS-1"}}, config.GetOverheadMappings())
+}
+
+func TestOverheadAddCmd_InvalidTicket(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetArgs([]string{"add", "Meetings", "meetings"})
+	err := cmd.Execute()
+
+	assert.EqualError(t, err, "[meetings] is not a valid Jira ticket (e.g. ENG-123)")
+	assert.Empty(t, config.GetOverheadMappings())
+}
+
+func TestOverheadRemoveCmd(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetArgs([]string{"remove", "Meetings"})
+	assert.Nil(t, cmd.Execute())
+	assert.Empty(t, config.GetOverheadMappings())
+
+	cmd.SetArgs([]string{"remove", "Meetings"})
+	assert.NotNil(t, cmd.Execute())
+}
+
+func TestOverheadRenameCmd(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetArgs([]string{"rename", "Meetings", "Ceremonies"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []config.OverheadMapping{{Project: "Ceremonies", Ticket: "MGMT-1"}}, config.GetOverheadMappings())
+}
+
+func TestOverheadRenameCmd_TargetAlreadyMapped(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Ceremonies", Ticket: "MGMT-2"})
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetArgs([]string{"rename", "Meetings", "Ceremonies"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "MGMT-1", config.GetOverheadTicket(0, "Meetings"))
+}
+
+func TestOverheadExportAndImportCmd(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{ProjectID: 7, Project: "Meetings", Ticket: "MGMT-1"})
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Hiring", Ticket: "MGMT-2"})
+	path := filepath.Join(t.TempDir(), "overhead.csv")
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetArgs([]string{"export", "--output", path})
+	assert.Nil(t, cmd.Execute())
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "project_id,project,ticket\n,Hiring,MGMT-2\n7,Meetings,MGMT-1\n", string(content))
+
+	setupBasicConfig()
+	output := bytes.NewBufferString("")
+	cmd = NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"import", path})
+	assert.Nil(t, cmd.Execute())
+	assert.Equal(t, "2 overhead ticket(s) imported\n", output.String())
+	assert.Equal(t, []config.OverheadMapping{{Project: "Hiring", Ticket: "MGMT-2"}, {ProjectID: 7, Project: "Meetings", Ticket: "MGMT-1"}}, config.GetOverheadMappings())
+}
+
+func TestOverheadImportCmd_InvalidRow(t *testing.T) {
+	setupBasicConfig()
+	path := filepath.Join(t.TempDir(), "overhead.csv")
+	assert.Nil(t, os.WriteFile(path, []byte("7,Meetings,MGMT-1\n,Hiring,hiring\n"), 0600))
+
+	cmd := NewOverheadCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{})
+	cmd.SetArgs([]string{"import", path})
+	err := cmd.Execute()
+
+	assert.EqualError(t, err, "line 2: [hiring] is not a valid Jira ticket (e.g. ENG-123)")
+	assert.Empty(t, config.GetOverheadMappings())
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/spf13/cobra"
+)
+
+// NewProfileCmd creates a new Cobra Command that helps managing configuration profiles
+func NewProfileCmd(configManager config.Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage configuration profiles",
+		Long:  "Manage configuration profiles, so toggl-sync can be used with different Toggl and Jira accounts",
+	}
+	cmd.AddCommand(newProfileListCmd(configManager))
+	cmd.AddCommand(newProfileUseCmd(configManager))
+	return cmd
+}
+
+func newProfileListCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all configuration profiles",
+		Long:  "List all configuration profiles (the default profile is marked with an asterisk)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := initExistingConfig(configManager); err != nil {
+				return err
+			}
+
+			for _, profile := range config.GetProfiles() {
+				marker := " "
+				if profile == config.GetDefaultProfile() {
+					marker = "*"
+				}
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", marker, profile); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func newProfileUseCmd(configManager config.Manager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Select the default configuration profile",
+		Long:  "Select the configuration profile used when no --profile flag is provided",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := initExistingConfig(configManager); err != nil {
+				return err
+			}
+
+			if !config.HasProfile(args[0]) {
+				return fmt.Errorf("profile [%s] does not exist! Please, run 'configure --profile %s' to create it", args[0], args[0])
+			}
+			config.SetDefaultProfile(args[0])
+
+			if err := configManager.Persist(); err != nil {
+				return fmt.Errorf("error saving configuration to file: %s", err)
+			}
+			return nil
+		},
+	}
+}
+
+func initExistingConfig(configManager config.Manager) error {
+	ok, err := configManager.Init()
+	if err != nil {
+		return fmt.Errorf("error reading configuration file: %s", err)
+	}
+	if !ok {
+		return fmt.Errorf("no configuration file exists! Please, run 'configure' to create a new configuration file")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileListCmd(t *testing.T) {
+	setupProfiles()
+	output := bytes.NewBufferString("")
+
+	cmd := NewProfileCmd(&MockConfigManager{InitOk: true})
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"list"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "  clienta\n* clientb\n", output.String())
+}
+
+func TestProfileListCmd_NoConfig(t *testing.T) {
+	cmd := NewProfileCmd(&MockConfigManager{InitOk: false})
+	cmd.SetArgs([]string{"list"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
+
+func TestProfileUseCmd(t *testing.T) {
+	setupProfiles()
+
+	cmd := NewProfileCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"use", "clienta"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "clienta", config.GetDefaultProfile())
+}
+
+func TestProfileUseCmd_UnknownProfile(t *testing.T) {
+	setupProfiles()
+
+	cmd := NewProfileCmd(&MockConfigManager{InitOk: true})
+	cmd.SetArgs([]string{"use", "clientc"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "clientb", config.GetDefaultProfile())
+}
+
+func TestProfileUseCmd_ErrorPersistingConfig(t *testing.T) {
+	setupProfiles()
+
+	cmd := NewProfileCmd(&MockConfigManager{InitOk: true, PersistError: errors.New("stub error persisting config")})
+	cmd.SetArgs([]string{"use", "clienta"})
+	err := cmd.Execute()
+
+	assert.NotNil(t, err)
+}
+
+func setupProfiles() {
+	config.Reset()
+	config.UseProfile("clienta")
+	config.Set(config.TogglUsername, "UserA")
+	config.UseProfile("clientb")
+	config.Set(config.TogglUsername, "UserB")
+	config.UseProfile("")
+	config.SetDefaultProfile("clientb")
+}
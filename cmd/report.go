@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+// Available report groupings
+const (
+	groupByTicket  = "ticket"
+	groupByProject = "project"
+	groupByTag     = "tag"
+	groupByDay     = "day"
+)
+
+// Available report formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+const (
+	unassignedGroup = "(unassigned)"
+	unmappedGroup   = "(unmapped)"
+	untaggedGroup   = "(untagged)"
+)
+
+// NewReportCmd creates a new Cobra Command that prints a breakdown of the time tracked over a period
+func NewReportCmd(configManager config.Manager, sources map[string]source.TimeSource) *cobra.Command {
+	var from, to, groupBy, format string
+	var sourceNames []string
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Print a breakdown of the time tracked over a period",
+		Long: "Print a breakdown of the time tracked over a period (both dates included), grouped by ticket, project, tag or day. " +
+			"Entries excluded from syncs are left out. Nothing is logged on Jira.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateReportOptions(groupBy, format); err != nil {
+				return err
+			}
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+			sourceNames = configuredSources(sourceNames)
+			if missing := missingSourcesConfig(sourceNames); len(missing) != 0 {
+				return fmt.Errorf("configuration file is invalid (missing %s)! Please, run 'configure' to create a new configuration file", strings.Join(missing, ", "))
+			}
+			timeSource, err := selectSources(sources, sourceNames)
+			if err != nil {
+				return err
+			}
+			location, err := userLocation(timeSource, timezoneFlag(cmd))
+			if err != nil {
+				return err
+			}
+			start, end, err := reportPeriod(from, to, location)
+			if err != nil {
+				return err
+			}
+
+			entries, err := timeSource.GetTimeEntries(start, end)
+			if err != nil {
+				return fmt.Errorf("error retrieving time entries: %s", err)
+			}
+			projects := newProjectCache()
+			entries, _, err = excludeEntries(timeSource, projects, entries, entryFilters{})
+			if err != nil {
+				return err
+			}
+			groups, total, err := groupEntries(timeSource, projects, entries, groupBy, location)
+			if err != nil {
+				return err
+			}
+
+			result := newReport(start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"), groupBy, groups, total)
+			return printReport(cmd.OutOrStdout(), result, format)
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "first date of the period, in YYYY-MM-DD format (required)")
+	cmd.Flags().StringVar(&to, "to", "", "last date of the period, in YYYY-MM-DD format (defaults to --from)")
+	cmd.Flags().StringVar(&groupBy, "group-by", groupByTicket, "grouping of the time tracked: ticket, project, tag or day")
+	cmd.Flags().StringVar(&format, "format", formatTable, "output format: table, json or csv")
+	cmd.Flags().StringSliceVar(&sourceNames, "source", nil, "time entry source(s) to report on: toggl, clockify, csv or ics (defaults to the ones in config, or toggl)")
+	_ = cmd.MarkFlagRequired("from")
+	return cmd
+}
+
+type report struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	GroupBy string      `json:"groupBy"`
+	Total   int         `json:"totalSeconds"`
+	Rows    []reportRow `json:"groups"`
+}
+
+type reportRow struct {
+	Group      string  `json:"group"`
+	Seconds    int     `json:"seconds"`
+	Percentage float64 `json:"percentage"`
+}
+
+// reportPeriod returns the start of the first day of the period, and the end of the last one, in the location provided
+func reportPeriod(from string, to string, location *time.Location) (start time.Time, end time.Time, err error) {
+	start, err = time.ParseInLocation("2006-01-02", from, location)
+	if err != nil {
+		return start, end, fmt.Errorf("error parsing --from date: %s", err)
+	}
+	end = start
+	if to != "" {
+		end, err = time.ParseInLocation("2006-01-02", to, location)
+		if err != nil {
+			return start, end, fmt.Errorf("error parsing --to date: %s", err)
+		}
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("invalid period: --to date is before --from date")
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+func validateReportOptions(groupBy string, format string) error {
+	switch groupBy {
+	case groupByTicket, groupByProject, groupByTag, groupByDay:
+	default:
+		return fmt.Errorf("unknown grouping [%s]; available groupings: ticket, project, tag or day", groupBy)
+	}
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+	default:
+		return fmt.Errorf("unknown format [%s]; available formats: table, json or csv", format)
+	}
+	return nil
+}
+
+// groupEntries adds up the duration of the (stopped) entries per group, and in total.
+// Entries with several tags count towards every one of them when grouping by tag (but only once towards the total),
+// and entries count towards the day they started in the location provided when grouping by day.
+func groupEntries(timeSource source.TimeSource, projects *projectCache, entries []api.TimeEntry, groupBy string, location *time.Location) (groups map[string]int, total int, err error) {
+	stopped := stoppedEntries(entries)
+	for _, entry := range stopped {
+		total += entry.Duration
+	}
+
+	groups = make(map[string]int)
+	switch groupBy {
+	case groupByTag:
+		for _, entry := range stopped {
+			if len(entry.Tags) == 0 {
+				groups[untaggedGroup] += entry.Duration
+			}
+			for _, tag := range entry.Tags {
+				groups[tag] += entry.Duration
+			}
+		}
+	case groupByDay:
+		for _, entry := range stopped {
+			groups[entry.Start.In(location).Format("2006-01-02")] += entry.Duration
+		}
+	default:
+		for _, entry := range summarize(stopped, summarizeDescription, location) {
+			group, err := classifyEntry(timeSource, projects, entry, groupBy)
+			if err != nil {
+				return nil, 0, err
+			}
+			groups[group] += entry.Duration
+		}
+	}
+	return groups, total, nil
+}
+
+// stoppedEntries leaves out running entries (i.e. those with a negative duration)
+func stoppedEntries(entries []api.TimeEntry) []api.TimeEntry {
+	var stopped []api.TimeEntry
+	for _, entry := range entries {
+		if entry.Duration < 0 {
+			log.Printf("Skipping entry [%s]; it is still running", entry.Description)
+			continue
+		}
+		stopped = append(stopped, entry)
+	}
+	return stopped
+}
+
+// classifyEntry returns the ticket (or project) an entry would be logged on, following the same rules as the sync
+func classifyEntry(timeSource source.TimeSource, projects *projectCache, entry api.TimeEntry, groupBy string) (string, error) {
+	if isJiraTicket(entry) {
+		if groupBy == groupByProject {
+			return strings.SplitN(entry.Description, "-", 2)[0], nil
+		}
+		return entryTicket(entry), nil
+	} else if entry.Pid == 0 {
+		return unassignedGroup, nil
+	}
+
+	name, err := projectName(timeSource, projects, entry.Pid)
+	if err != nil {
+		return "", err
+	}
+
+	if groupBy == groupByProject {
+		return name, nil
+	} else if ticket := config.GetOverheadTicket(entry.Pid, name); ticket != "" {
+		return ticket, nil
+	}
+	return unmappedGroup, nil
+}
+
+func newReport(from string, to string, groupBy string, groups map[string]int, total int) report {
+	result := report{From: from, To: to, GroupBy: groupBy, Total: total, Rows: []reportRow{}}
+	for group, seconds := range groups {
+		row := reportRow{Group: group, Seconds: seconds}
+		if result.Total > 0 {
+			row.Percentage = float64(seconds) * 100 / float64(result.Total)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	// Days are listed chronologically; anything else, from the most to the least time tracked
+	sort.Slice(result.Rows, func(i, j int) bool {
+		if groupBy != groupByDay && result.Rows[i].Seconds != result.Rows[j].Seconds {
+			return result.Rows[i].Seconds > result.Rows[j].Seconds
+		}
+		return result.Rows[i].Group < result.Rows[j].Group
+	})
+	return result
+}
+
+func printReport(out io.Writer, result report, format string) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case formatCSV:
+		writer := csv.NewWriter(out)
+		_ = writer.Write([]string{result.GroupBy, "seconds", "hours", "percentage"})
+		for _, row := range result.Rows {
+			_ = writer.Write([]string{row.Group, strconv.Itoa(row.Seconds), formatHours(row.Seconds), formatPercentage(row.Percentage)})
+		}
+		_ = writer.Write([]string{"total", strconv.Itoa(result.Total), formatHours(result.Total), formatPercentage(100)})
+		writer.Flush()
+		return writer.Error()
+	default:
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(writer, "%s\thours\t%%\n", strings.ToUpper(result.GroupBy))
+		for _, row := range result.Rows {
+			_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", row.Group, formatHours(row.Seconds), formatPercentage(row.Percentage))
+		}
+		_, _ = fmt.Fprintf(writer, "TOTAL\t%s\t%s\n", formatHours(result.Total), formatPercentage(100))
+		return writer.Flush()
+	}
+}
+
+func formatHours(seconds int) string {
+	return strconv.FormatFloat(float64(seconds)/3600, 'f', 2, 64)
+}
+
+func formatPercentage(percentage float64) string {
+	return strconv.FormatFloat(percentage, 'f', 1, 64)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var reportTestEntries = []api.TimeEntry{
+	{Id: 1, Start: time.Date(2020, 5, 21, 9, 0, 0, 0, time.Local), Duration: 3600, Description: "ENG-1001", Tags: []string{"dev"}},
+	{Id: 2, Start: time.Date(2020, 5, 22, 9, 0, 0, 0, time.Local), Duration: 1800, Description: "ENG-1001", Tags: []string{"dev", "review"}},
+	{Id: 3, Start: time.Date(2020, 5, 22, 11, 0, 0, 0, time.Local), Pid: 7, Duration: 1800, Description: "Team catch-up"},
+	{Id: 4, Start: time.Date(2020, 5, 22, 12, 0, 0, 0, time.Local), Duration: -1590141600, Description: "ENG-1002"},
+}
+
+func TestReportCmd_GroupByTicket(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+
+	output := runReport(t, "--from", "2020-05-21", "--to", "2020-05-22")
+
+	assert.Equal(t, ""+
+		"TICKET    hours  %\n"+
+		"ENG-1001  1.50   75.0\n"+
+		"MGMT-1    0.50   25.0\n"+
+		"TOTAL     2.00   100.0\n", output)
+}
+
+func TestReportCmd_GroupByProject_JSON(t *testing.T) {
+	setupBasicConfig()
+
+	output := runReport(t, "--from", "2020-05-21", "--to", "2020-05-22", "--group-by", "project", "--format", "json")
+
+	var result report
+	assert.Nil(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, report{
+		From:    "2020-05-21",
+		To:      "2020-05-22",
+		GroupBy: "project",
+		Total:   7200,
+		Rows: []reportRow{
+			{Group: "ENG", Seconds: 5400, Percentage: 75},
+			{Group: "Meetings", Seconds: 1800, Percentage: 25},
+		},
+	}, result)
+}
+
+func TestReportCmd_GroupByTag_CSV(t *testing.T) {
+	setupBasicConfig()
+
+	output := runReport(t, "--from", "2020-05-21", "--to", "2020-05-22", "--group-by", "tag", "--format", "csv")
+
+	assert.Equal(t, ""+
+		"tag,seconds,hours,percentage\n"+
+		"dev,5400,1.50,75.0\n"+
+		"(untagged),1800,0.50,25.0\n"+
+		"review,1800,0.50,25.0\n"+
+		"total,7200,2.00,100.0\n", output)
+}
+
+func TestReportCmd_GroupByDay(t *testing.T) {
+	setupBasicConfig()
+
+	output := runReport(t, "--from", "2020-05-21", "--to", "2020-05-22", "--group-by", "day", "--format", "csv")
+
+	assert.Equal(t, ""+
+		"day,seconds,hours,percentage\n"+
+		"2020-05-21,3600,1.00,50.0\n"+
+		"2020-05-22,3600,1.00,50.0\n"+
+		"total,7200,2.00,100.0\n", output)
+}
+
+func TestReportCmd_ExcludedEntries(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncExcludeTags, "review")
+	config.Set(config.SyncExcludeProjects, "Meetings")
+
+	output := runReport(t, "--from", "2020-05-21", "--to", "2020-05-22")
+
+	assert.Equal(t, ""+
+		"TICKET    hours  %\n"+
+		"ENG-1001  1.00   100.0\n"+
+		"TOTAL     1.00   100.0\n", output)
+}
+
+func TestReportCmd_InvalidOptions(t *testing.T) {
+	setupBasicConfig()
+
+	for _, args := range [][]string{
+		{"--to", "2020-05-22"},
+		{"--from", "2020-05-22", "--to", "2020-05-21"},
+		{"--from", "2020-05-22", "--group-by", "client"},
+		{"--from", "2020-05-22", "--format", "xml"},
+	} {
+		cmd := NewReportCmd(&MockConfigManager{InitOk: true}, togglSources(&MockTogglAPI{}))
+		cmd.SetArgs(args)
+		assert.NotNil(t, cmd.Execute(), "Arguments %v should be rejected", args)
+	}
+}
+
+func runReport(t *testing.T, args ...string) string {
+	togglAPI := &MockTogglAPI{
+		TimeEntries: reportTestEntries,
+		Project:     api.Project{Data: api.ProjectData{Id: 7, Name: "Meetings"}},
+	}
+	output := bytes.NewBufferString("")
+
+	cmd := NewReportCmd(&MockConfigManager{InitOk: true}, togglSources(togglAPI))
+	cmd.SetOut(output)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	return output.String()
+}
@@ -8,13 +8,17 @@ import (
 
 	"github.com/javicg/toggl-sync/api"
 	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
 	"github.com/spf13/cobra"
 )
 
 // NewRootCmd creates a new Cobra Command that acts as entry point for all operations
-func NewRootCmd(configManager config.Manager, inputCtrl inputController, togglAPI api.TogglAPI, jiraAPI api.JiraAPI) *cobra.Command {
-	var dryRun bool
+func NewRootCmd(configManager config.Manager, inputCtrl inputController, sources map[string]source.TimeSource, sinks map[string]sink.WorklogSink, syncLedger ledger.Ledger) *cobra.Command {
+	var opts syncOptions
 	var syncCurrentDate bool
+	var sourceNames []string
 	cmd := &cobra.Command{
 		Use:   "toggl-sync [date]",
 		Short: "Synchronize time entries to Jira",
@@ -25,17 +29,34 @@ func NewRootCmd(configManager config.Manager, inputCtrl inputController, togglAP
 			if err != nil {
 				return err
 			}
-			if err = readConfig(configManager); err != nil {
+			if err = readConfig(configManager, profileFlag(cmd)); err != nil {
 				return err
 			}
-			if err = validateConfig(); err != nil {
+			sourceNames = configuredSources(sourceNames)
+			if err = validateConfig(sourceNames); err != nil {
 				return err
 			}
-			if err = sync(inputCtrl, togglAPI, jiraAPI, syncDate, dryRun); err != nil {
+			timeSource, err := selectSources(sources, sourceNames)
+			if err != nil {
+				return err
+			}
+			opts.timezone = timezoneFlag(cmd)
+			if syncCurrentDate {
+				if syncDate, err = currentDate(timeSource, opts.timezone); err != nil {
+					return err
+				}
+			}
+			if _, err = runningMode(opts.running); err != nil {
+				return err
+			}
+			if _, err = summarizeStrategy(opts.summarize); err != nil {
+				return err
+			}
+			if err = sync(inputCtrl, timeSource, sinks, syncLedger, syncDate, opts); err != nil {
 				return err
 			}
 
-			if !dryRun {
+			if !opts.dryRun {
 				if err := configManager.Persist(); err != nil {
 					return err
 				}
@@ -43,22 +64,40 @@ func NewRootCmd(configManager config.Manager, inputCtrl inputController, togglAP
 			return err
 		},
 	}
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "dry-run toggl-sync (avoid side effects)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "dry-run toggl-sync (avoid side effects)")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "sync the date again, even if it was already synced")
 	cmd.Flags().BoolVarP(&syncCurrentDate, "current-date", "c", false, "sync the current date (no date argument required)")
+	cmd.Flags().StringSliceVar(&sourceNames, "source", nil, "time entry source(s) to sync from: toggl, clockify, csv or ics (defaults to the ones in config, or toggl)")
+	cmd.Flags().StringVar(&opts.running, "running", "", "how to handle running entries: fail, skip, stopped or partial (defaults to the one in config, or fail)")
+	cmd.Flags().BoolVar(&opts.stopRunning, "stop-running", false, "stop running timers before syncing")
+	cmd.Flags().StringVar(&opts.summarize, "summarize", "", "how to merge entries into worklogs: none, description, ticket or ticket-per-day (defaults to the one in config, or description)")
+	cmd.Flags().BoolVar(&opts.filters.billableOnly, "billable-only", false, "sync billable entries only")
+	cmd.Flags().StringSliceVar(&opts.filters.clients, "client", nil, "sync only the entries of projects done for these Toggl client(s) (names or IDs)")
+	cmd.PersistentFlags().String("profile", "", "configuration profile to use (defaults to the one selected with 'profile use')")
+	cmd.PersistentFlags().String("tz", "", "time zone days start and end in, e.g. Europe/Madrid (defaults to the one in config, then the one in the Toggl profile)")
 	return cmd
 }
 
+// profileFlag returns the value of the global --profile flag (empty if the command does not inherit it)
+func profileFlag(cmd *cobra.Command) string {
+	if flag := cmd.Flags().Lookup("profile"); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
+}
+
+// extractDateToSync returns the date passed down, or an empty one when syncing the current date (see currentDate)
 func extractDateToSync(args []string, syncCurrentDate bool) (syncDate string, err error) {
 	if len(args) == 1 && !syncCurrentDate {
 		return args[0], nil
 	}
 	if len(args) == 0 && syncCurrentDate {
-		return time.Now().Format("2006-01-02"), nil
+		return "", nil
 	}
 	return "", fmt.Errorf("invalid arguments. Please, pass down a date (e.g. toggl-sync 2020-12-01) or use the correct flag to sync the current date")
 }
 
-func readConfig(configManager config.Manager) error {
+func readConfig(configManager config.Manager, profile string) error {
 	ok, err := configManager.Init()
 	if err != nil {
 		return fmt.Errorf("unable to read configuration: %s", err)
@@ -68,76 +107,165 @@ func readConfig(configManager config.Manager) error {
 		return fmt.Errorf("no configuration file exists! Please, run 'configure' to create a new configuration file")
 	}
 
+	if profile == "" {
+		profile = config.GetDefaultProfile()
+	}
+	if profile != "" && !config.HasProfile(profile) {
+		return fmt.Errorf("profile [%s] does not exist! Please, run 'configure --profile %s' to create it", profile, profile)
+	}
+	config.UseProfile(profile)
+
 	log.Printf("Configuration read from: %s", config.FileUsed())
+	if profile != "" {
+		log.Printf("Using profile: %s", profile)
+	}
+
+	origin, err := config.LoadTeamConfig()
+	if err != nil {
+		return fmt.Errorf("unable to read team configuration: %s", err)
+	} else if origin != "" {
+		log.Printf("Team configuration read from: %s", origin)
+		for _, value := range config.OverriddenTeamValues() {
+			log.Printf("Personal configuration overrides the team value of %s", value)
+		}
+	}
 	return nil
 }
 
-func validateConfig() error {
-	isValid :=
-		config.Get(config.TogglServerURL) != "" &&
-			config.Get(config.TogglUsername) != "" &&
-			config.Get(config.TogglPassword) != "" &&
-			config.Get(config.JiraServerURL) != "" &&
-			config.Get(config.JiraUsername) != "" &&
-			config.Get(config.JiraPassword) != "" &&
-			len(config.GetSlice(config.JiraProjectKey)) != 0
+func validateConfig(sourceNames []string) error {
+	return validateKeys(missingConfig(sourceNames))
+}
 
-	if !isValid {
-		return fmt.Errorf("configuration file is invalid! Please, run 'configure' to create a new configuration file")
+// validateKeys reports the config keys that are required but have no value (if any), and checks the visibility rules
+func validateKeys(missing []string) error {
+	if len(missing) != 0 {
+		return fmt.Errorf("configuration file is invalid (missing %s)! Please, run 'configure' to create a new configuration file", strings.Join(missing, ", "))
 	}
-	return nil
+	return validateVisibilityRules()
 }
 
-func sync(inputCtrl inputController, togglAPI api.TogglAPI, jiraAPI api.JiraAPI, syncDate string, dryRun bool) error {
-	err := printUserDetails(togglAPI)
+// missingConfig returns the config keys required to sync from the sources (to the selected sinks) that have no value
+func missingConfig(sourceNames []string) []string {
+	missing := missingKeys(config.JiraProjectKey)
+	missing = append(missing, missingSourcesConfig(sourceNames)...)
+	return append(missing, missingSinksConfig()...)
+}
+
+// syncOptions modify the behaviour of a sync
+type syncOptions struct {
+	dryRun      bool
+	force       bool
+	skipSynced  bool
+	timezone    string
+	running     string
+	summarize   string
+	stopRunning bool
+	filters     entryFilters
+	// logger reports the progress of the sync (the standard logger if nil)
+	logger *log.Logger
+}
+
+func sync(inputCtrl inputController, timeSource source.TimeSource, sinks map[string]sink.WorklogSink, syncLedger ledger.Ledger, syncDate string, opts syncOptions) error {
+	if opts.logger == nil {
+		opts.logger = log.Default()
+	}
+	logger := opts.logger
+	sinkNames, err := selectSinks(sinks)
+	if err != nil {
+		return err
+	}
+	strategy, err := summarizeStrategy(opts.summarize)
 	if err != nil {
 		return err
 	}
 
-	entries, err := getTimeEntriesForDate(togglAPI, syncDate)
+	record, err := syncLedger.Get(syncDate)
+	if err != nil {
+		return fmt.Errorf("error reading sync ledger: %s", err)
+	}
+	if record == nil || opts.force {
+		record = &ledger.Record{Date: syncDate}
+	} else if record.Complete && opts.skipSynced {
+		logger.Printf("Date [%s] was already synced (%s). Use --force to sync it again.", syncDate, record.SyncedAt.Format(time.RFC1123))
+		return nil
+	} else if record.Complete {
+		logger.Printf("Date [%s] was already synced (%s); only time tracked since will be logged. Use --force to sync it all again.", syncDate, record.SyncedAt.Format(time.RFC1123))
+	}
+
+	me, err := printUserDetails(logger, timeSource)
+	if err != nil {
+		return err
+	}
+	location, err := resolveLocation(logger, opts.timezone, me)
+	if err != nil {
+		return err
+	}
+
+	entries, err := getTimeEntriesForDate(timeSource, syncDate, location)
+	if err != nil {
+		return err
+	}
+	projects := newProjectCache()
+	entries, excluded, err := excludeEntries(timeSource, projects, entries, opts.filters)
+	if err != nil {
+		return err
+	}
+	entries, pending, err := handleRunningEntries(timeSource, entries, opts, time.Now())
 	if err != nil {
 		return err
 	}
 
-	entries = summarize(entries)
-	printSummary(syncDate, entries)
+	entries = summarize(entries, strategy, location)
+	printSummary(logger, syncDate, entries, excluded)
 
-	ok, message := validateEntries(entries)
+	ok, message := validateEntries(logger, entries)
 	if !ok {
-		log.Print("Found issues during validation:")
-		log.Print(message)
-		log.Print("Please, correct the time entries above and try again.")
+		logger.Print("Found issues during validation:")
+		logger.Print(message)
+		logger.Print("Please, correct the time entries above and try again.")
 		return fmt.Errorf("validation failed")
 	}
 
-	if dryRun {
-		log.Print("Logging work on Jira... SKIPPED! (dry-run)")
+	if opts.dryRun {
+		logger.Printf("Logging work on %s... SKIPPED! (dry-run)", strings.Join(sinkNames, ", "))
 		return nil
 	}
 
-	logWorkOnJira(inputCtrl, togglAPI, jiraAPI, entries)
+	failures := logWork(logger, inputCtrl, timeSource, projects, sinks, sinkNames, record, location, entries)
+	record.Complete = failures == 0 && !pending
+	record.SyncedAt = time.Now()
+	if err = syncLedger.Save(*record); err != nil {
+		return fmt.Errorf("error saving sync ledger: %s", err)
+	}
 	return nil
 }
 
-func printUserDetails(togglAPI api.TogglAPI) error {
-	log.Print("Fetching user details...")
-	me, err := togglAPI.GetMe()
+// printUserDetails prints the details of the user of the time source, and returns them (nil if the source provides none)
+func printUserDetails(logger *log.Logger, timeSource source.TimeSource) (*api.Me, error) {
+	provider, ok := timeSource.(source.UserDetailsProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	logger.Print("Fetching user details...")
+	me, err := provider.GetMe()
 	if err != nil {
-		return fmt.Errorf("error fetching user details: %s", err)
+		return nil, fmt.Errorf("error fetching user details: %s", err)
 	}
 
-	log.Print("User details:")
-	log.Printf("Name = %s, Email = %s\n", me.Data.Fullname, me.Data.Email)
-	return nil
+	logger.Print("User details:")
+	logger.Printf("Name = %s, Email = %s\n", me.Data.Fullname, me.Data.Email)
+	return me, nil
 }
 
-func getTimeEntriesForDate(togglAPI api.TogglAPI, dateStr string) ([]api.TimeEntry, error) {
-	startDate, err := time.Parse("2006-01-02", dateStr)
+// getTimeEntriesForDate retrieves the time entries of the day, which starts at midnight in the location provided
+func getTimeEntriesForDate(timeSource source.TimeSource, dateStr string, location *time.Location) ([]api.TimeEntry, error) {
+	startDate, err := time.ParseInLocation("2006-01-02", dateStr, location)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing input date: %s", err)
 	}
 
-	entries, err := togglAPI.GetTimeEntries(startDate, startDate.AddDate(0, 0, 1))
+	entries, err := timeSource.GetTimeEntries(startDate, startDate.AddDate(0, 0, 1))
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving time entries: %s", err)
 	}
@@ -145,8 +273,8 @@ func getTimeEntriesForDate(togglAPI api.TogglAPI, dateStr string) ([]api.TimeEnt
 	return entries, nil
 }
 
-func validateEntries(entries []api.TimeEntry) (ok bool, message string) {
-	log.Print("Validating time entries...")
+func validateEntries(logger *log.Logger, entries []api.TimeEntry) (ok bool, message string) {
+	logger.Print("Validating time entries...")
 	ok, message = true, ""
 	for _, entry := range entries {
 		entryOk, entryMessage := validateEntry(entry)
@@ -169,90 +297,183 @@ func validateEntry(entry api.TimeEntry) (ok bool, message string) {
 
 func isJiraTicket(entry api.TimeEntry) bool {
 	match := false
-	for _, projectKey := range config.GetSlice(config.JiraProjectKey) {
+	for _, projectKey := range config.GetAllJiraProjectKeys() {
 		match = match || strings.HasPrefix(entry.Description, projectKey)
 	}
 	return match
 }
 
-func summarize(entries []api.TimeEntry) []api.TimeEntry {
-	type CacheKey struct {
-		Pid         int
-		Description string
+func printSummary(logger *log.Logger, syncDate string, entries []api.TimeEntry, excluded []excludedEntry) {
+	logger.Printf("== Time Entries Summary (%s) ==", syncDate)
+	for i := range entries {
+		logger.Printf("Entry: %s || Duration (s): %d\n", entries[i].Description, entries[i].Duration)
+	}
+	if len(excluded) == 0 {
+		return
 	}
 
-	cache := make(map[CacheKey]api.TimeEntry)
-	for _, entry := range entries {
-		key := CacheKey{
-			Pid:         entry.Pid,
-			Description: entry.Description,
-		}
-		if cachedEntry, ok := cache[key]; ok {
-			cache[key] = api.TimeEntry{
-				Pid:         cachedEntry.Pid,
-				Duration:    cachedEntry.Duration + entry.Duration,
-				Description: entry.Description,
-			}
-		} else {
-			cache[key] = entry
+	excludedSeconds := 0
+	for _, entry := range excluded {
+		if entry.Duration < 0 {
+			logger.Printf("Excluded: %s || Still running || Rule: %s\n", entry.Description, entry.Reason)
+			continue
 		}
+		logger.Printf("Excluded: %s || Duration (s): %d || Rule: %s\n", entry.Description, entry.Duration, entry.Reason)
+		excludedSeconds += entry.Duration
 	}
-	var summary []api.TimeEntry
-	for _, entry := range cache {
-		summary = append(summary, entry)
-	}
-	return summary
+	logger.Printf("%d entry(ies) excluded, [%d]s in total", len(excluded), excludedSeconds)
 }
 
-func printSummary(syncDate string, entries []api.TimeEntry) {
-	log.Printf("== Time Entries Summary (%s) ==", syncDate)
-	for i := range entries {
-		log.Printf("Entry: %s || Duration (s): %d\n", entries[i].Description, entries[i].Duration)
+// logWork writes all entries to every sink, and returns the number of failures.
+// Entries already in the ledger record (matched by entry ids, see ledger.Record.Synced) only get the time tracked since logged,
+// and partial worklogs are updated instead.
+func logWork(logger *log.Logger, inputCtrl inputController, timeSource source.TimeSource, projects *projectCache, sinks map[string]sink.WorklogSink, sinkNames []string, record *ledger.Record, location *time.Location, entries []api.TimeEntry) (failures int) {
+	logger.Printf("Logging work on %s...", strings.Join(sinkNames, ", "))
+	// Only worklogs of previous syncs count as synced; entries of this sync are never matched against each other
+	previous := ledger.Record{Date: record.Date, Worklogs: append([]ledger.Worklog(nil), record.Worklogs...)}
+	for _, entry := range entries {
+		worklog, err := createWorklog(inputCtrl, timeSource, projects, record.Date, location, entry)
+		if err != nil {
+			logger.Printf("No time logged for [%s]; %s", entry.Description, err)
+			failures++
+			continue
+		}
+		entryIDs := knownIDs(entry.SourceIds)
+
+		for _, name := range sinkNames {
+			logged, partial := 0, (*ledger.Worklog)(nil)
+			for _, synced := range previous.Synced(name, worklog.Ticket, worklog.Description, entryIDs) {
+				if synced.Partial && partial == nil {
+					partial = &synced
+				} else {
+					logged += synced.Seconds
+				}
+			}
+
+			remaining := worklog
+			remaining.TimeSpent = worklog.TimeSpent - time.Duration(logged)*time.Second
+			if partial == nil && remaining.TimeSpent <= 0 {
+				logger.Printf("Skipping entry [%s] on %s; [%d]s were already logged", worklog.Description, name, logged)
+				continue
+			} else if partial == nil && logged != 0 {
+				logger.Printf("Entry [%s] was already logged on %s; logging the [%d]s tracked since", worklog.Description, name, int(remaining.TimeSpent.Seconds()))
+			}
+			if _, canUpdate := sinks[name].(sink.WorklogUpdater); remaining.Partial && !canUpdate {
+				logger.Printf("Skipping entry [%s] on %s; it is still running, and %s worklogs cannot be updated later", worklog.Description, name, name)
+				continue
+			}
+
+			worklogID, err := writeWorklog(logger, name, sinks[name], remaining, partial)
+			if err != nil {
+				failures++
+				continue
+			}
+			synced := ledger.Worklog{
+				Sink:        name,
+				Ticket:      worklog.Ticket,
+				Description: worklog.Description,
+				Seconds:     int(remaining.TimeSpent.Seconds()),
+				EntryIDs:    entryIDs,
+				WorklogID:   worklogID,
+				Partial:     worklog.Partial,
+			}
+			if partial != nil {
+				synced.WorklogID = partial.WorklogID
+				replaceWorklog(record, *partial, synced)
+			} else {
+				record.Worklogs = append(record.Worklogs, synced)
+			}
+		}
 	}
+	return
 }
 
-func logWorkOnJira(inputCtrl inputController, togglAPI api.TogglAPI, jiraAPI api.JiraAPI, entries []api.TimeEntry) {
-	log.Print("Logging work on Jira...")
-	for _, entry := range entries {
-		if isJiraTicket(entry) {
-			logProjectWorkOnJira(jiraAPI, entry)
-		} else {
-			logOverheadWorkOnJira(inputCtrl, togglAPI, jiraAPI, entry)
+// knownIDs leaves out the ids of entries whose source does not identify them (i.e. 0)
+func knownIDs(ids []int) []int {
+	var known []int
+	for _, id := range ids {
+		if id != 0 {
+			known = append(known, id)
 		}
 	}
+	return known
 }
 
-func logProjectWorkOnJira(jiraAPI api.JiraAPI, entry api.TimeEntry) {
-	err := jiraAPI.LogWork(entry.Description, time.Duration(entry.Duration)*time.Second)
-	if err != nil {
-		log.Printf("No time logged for [%s]; operation failed with an error: %s", entry.Description, err)
-	} else {
-		log.Printf("Successfully logged [%d]s for entry [%s]", entry.Duration, entry.Description)
+// createWorklog resolves the ticket the entry is logged on, and renders its comment. The worklog starts when the entry did
+// (or at midnight, if the source does not tell), in the location provided.
+func createWorklog(inputCtrl inputController, timeSource source.TimeSource, projects *projectCache, syncDate string, location *time.Location, entry api.TimeEntry) (worklog sink.Worklog, err error) {
+	started := entry.Start
+	if started.IsZero() {
+		started, _ = time.ParseInLocation("2006-01-02", syncDate, location)
+	}
+	worklog = sink.Worklog{
+		Date:        syncDate,
+		Started:     started.In(location),
+		Ticket:      entryTicket(entry),
+		TimeSpent:   time.Duration(entry.Duration) * time.Second,
+		Description: entry.Description,
+		Partial:     entry.Running,
+		Billable:    entry.Billable,
+	}
+	if isJiraTicket(entry) {
+		worklog.Comment, err = worklogComment(timeSource, projects, location, worklog, entry)
+		return worklog, err
 	}
-}
 
-func logOverheadWorkOnJira(inputCtrl inputController, togglAPI api.TogglAPI, jiraAPI api.JiraAPI, entry api.TimeEntry) {
-	project, err := togglAPI.GetProjectById(entry.Pid)
+	project, err := projects.project(timeSource, entry.Pid)
 	if err != nil {
-		log.Printf("No time logged for [%s]; retrieving project information failed with an error: %s", entry.Description, err)
-		return
+		return worklog, err
 	}
 
-	if config.GetOverheadKey(project.Data.Name) == "" {
+	if config.GetOverheadTicket(project.Data.Id, project.Data.Name) == "" {
 		err = requestOverheadKey(inputCtrl, entry, project)
 		if err != nil {
-			log.Printf("No time logged for [%s]; requesting project overhead key failed with an error: %s", entry.Description, err)
-			return
+			return worklog, fmt.Errorf("requesting project overhead key failed with an error: %s", err)
 		}
 	}
 
-	key := config.GetOverheadKey(project.Data.Name)
-	err = jiraAPI.LogWorkWithUserDescription(key, time.Duration(entry.Duration)*time.Second, entry.Description)
-	if err != nil {
-		log.Printf("No time logged for [%s] (project [%s]); operation failed with an error: %s", entry.Description, project.Data.Name, err)
+	worklog.Ticket = config.GetOverheadTicket(project.Data.Id, project.Data.Name)
+	worklog.Project = project.Data.Name
+	worklog.ProjectID = project.Data.Id
+	worklog.Overhead = true
+	worklog.Comment, err = worklogComment(timeSource, projects, location, worklog, entry)
+	return worklog, err
+}
+
+// writeWorklog writes the worklog to the sink, returning its id if the sink may have to update it later (i.e. partial worklogs).
+// Worklogs previously written as partial are updated instead.
+func writeWorklog(logger *log.Logger, sinkName string, worklogSink sink.WorklogSink, worklog sink.Worklog, previous *ledger.Worklog) (id string, err error) {
+	updater, _ := worklogSink.(sink.WorklogUpdater)
+	switch {
+	case previous != nil:
+		err = updater.Update(previous.WorklogID, worklog)
+	case worklog.Partial:
+		id, err = updater.Add(worklog)
+	default:
+		err = worklogSink.Write(worklog)
+	}
+	if worklog.Overhead && err != nil {
+		logger.Printf("No time logged on %s for [%s] (project [%s]); operation failed with an error: %s", sinkName, worklog.Description, worklog.Project, err)
+	} else if worklog.Overhead {
+		logger.Printf("Successfully logged [%d]s on %s for entry [%s] (project [%s])", int(worklog.TimeSpent.Seconds()), sinkName, worklog.Description, worklog.Project)
+	} else if err != nil {
+		logger.Printf("No time logged on %s for [%s]; operation failed with an error: %s", sinkName, worklog.Description, err)
 	} else {
-		log.Printf("Successfully logged [%d]s for entry [%s] (project [%s])", entry.Duration, entry.Description, project.Data.Name)
+		logger.Printf("Successfully logged [%d]s on %s for entry [%s]", int(worklog.TimeSpent.Seconds()), sinkName, worklog.Description)
+	}
+	return id, err
+}
+
+// replaceWorklog replaces a worklog of the ledger record (e.g. a partial one, once updated)
+func replaceWorklog(record *ledger.Record, previous ledger.Worklog, worklog ledger.Worklog) {
+	for i, candidate := range record.Worklogs {
+		if candidate.Sink == previous.Sink && candidate.WorklogID == previous.WorklogID &&
+			candidate.Ticket == previous.Ticket && candidate.Description == previous.Description {
+			record.Worklogs[i] = worklog
+			return
+		}
 	}
+	record.Worklogs = append(record.Worklogs, worklog)
 }
 
 func requestOverheadKey(inputCtrl inputController, entry api.TimeEntry, project *api.Project) error {
@@ -264,6 +485,6 @@ func requestOverheadKey(inputCtrl inputController, entry api.TimeEntry, project
 	input = strings.TrimSpace(input)
 
 	log.Printf("Saving configuration: entries for project [%s] will be tracked as [%s] from now on", project.Data.Name, input)
-	config.SetOverheadKey(project.Data.Name, input)
+	config.SetOverheadMapping(config.OverheadMapping{ProjectID: project.Data.Id, Project: project.Data.Name, Ticket: input})
 	return nil
 }
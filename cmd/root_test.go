@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"log"
 	"testing"
 	"time"
 
 	"github.com/javicg/toggl-sync/api"
 	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -15,7 +20,7 @@ import (
 func TestRootCmd_MissingDate(t *testing.T) {
 	setupBasicConfig()
 
-	cmd := NewRootCmd(&MockConfigManager{}, RejectAllInputController{t: t}, &MockTogglAPI{}, &MockJiraAPI{})
+	cmd := NewRootCmd(&MockConfigManager{}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
 	cmd.SetArgs([]string{})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -24,7 +29,7 @@ func TestRootCmd_MissingDate(t *testing.T) {
 func TestRootCmd_ProvidingDateAndSyncingCurrentDate(t *testing.T) {
 	setupBasicConfig()
 
-	cmd := NewRootCmd(&MockConfigManager{}, RejectAllInputController{t: t}, &MockTogglAPI{}, &MockJiraAPI{})
+	cmd := NewRootCmd(&MockConfigManager{}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--current-date"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -36,7 +41,7 @@ func TestRootCmd_ErrorInitialisingConfig(t *testing.T) {
 	}
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, &MockTogglAPI{}, &MockJiraAPI{})
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -48,7 +53,7 @@ func TestRootCmd_InitConfigNotOk(t *testing.T) {
 	}
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, &MockTogglAPI{}, &MockJiraAPI{})
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -61,7 +66,21 @@ func TestRootCmd_InvalidConfig(t *testing.T) {
 
 	config.Reset()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, &MockTogglAPI{}, &MockJiraAPI{})
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestRootCmd_InvalidJiraInstanceConfig(t *testing.T) {
+	configManager := &MockConfigManager{
+		InitOk: true,
+	}
+
+	setupBasicConfig()
+	config.Set(config.JiraInstanceKey("ops", config.JiraServerURL), "http://localhost/ops")
+
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -119,9 +138,9 @@ func TestRootCmd(t *testing.T) {
 	jiraAPI := &MockJiraAPI{}
 
 	setupBasicConfig()
-	config.SetOverheadKey("testing", "ENG-1001")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "testing", Ticket: "ENG-1001"})
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -133,6 +152,100 @@ func TestRootCmd(t *testing.T) {
 	assert.NoError(t, jiraAPI.VerifyNoOtherWorkLogged())
 }
 
+func TestRootCmd_ProjectWorkOnJiraInstance(t *testing.T) {
+	configManager := &MockConfigManager{
+		InitOk: true,
+	}
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{
+				Id:          1,
+				Duration:    300,
+				Description: "OPS-1001",
+			},
+		},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	setupBasicConfig()
+	config.Set(config.JiraInstanceKey("ops", config.JiraServerURL), "http://localhost/ops")
+	config.Set(config.JiraInstanceKey("ops", config.JiraUsername), "OpsUser")
+	config.Set(config.JiraInstanceKey("ops", config.JiraPassword), "OpsPassword")
+	config.Set(config.JiraInstanceKey("ops", config.JiraProjectKey), []string{"OPS"})
+
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("OPS-1001", 300))
+	assert.NoError(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestRootCmd_Profile(t *testing.T) {
+	configManager := &MockConfigManager{
+		InitOk: true,
+	}
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{
+				Id:          1,
+				Duration:    300,
+				Description: "CLA-1001",
+			},
+		},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	config.Reset()
+	config.UseProfile("clienta")
+	setupProfileConfig()
+	config.Set(config.JiraProjectKey, []string{"CLA"})
+	config.UseProfile("")
+
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--profile", "clienta"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("CLA-1001", 300))
+	assert.NoError(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestRootCmd_DefaultProfile(t *testing.T) {
+	configManager := &MockConfigManager{
+		InitOk: true,
+	}
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{},
+	}
+
+	config.Reset()
+	config.UseProfile("clienta")
+	setupProfileConfig()
+	config.UseProfile("")
+	config.SetDefaultProfile("clienta")
+
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "clienta", config.ActiveProfile())
+}
+
+func TestRootCmd_UnknownProfile(t *testing.T) {
+	configManager := &MockConfigManager{
+		InitOk: true,
+	}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--profile", "clientc"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
 func TestRootCmd_NoTimeEntries(t *testing.T) {
 	configManager := &MockConfigManager{
 		InitOk: true,
@@ -150,7 +263,7 @@ func TestRootCmd_NoTimeEntries(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -173,7 +286,7 @@ func TestRootCmd_CurrentDate_NoTimeEntries(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"--current-date"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -213,9 +326,9 @@ func TestRootCmd_DryRun(t *testing.T) {
 	jiraAPI := &RejectAllCallsJiraAPI{t: t}
 
 	setupBasicConfig()
-	config.SetOverheadKey("testing", "ENG-1001")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "testing", Ticket: "ENG-1001"})
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -238,7 +351,7 @@ func TestRootCmd_DryRun_NoTimeEntries(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -255,7 +368,7 @@ func TestRootCmd_DryRun_ErrorFetchingUserDetails(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -277,7 +390,7 @@ func TestRootCmd_DryRun_ErrorParsingSyncDate(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2nd January 2006", "--dry-run"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -300,7 +413,7 @@ func TestRootCmd_DryRun_ErrorFetchingTimeEntries(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -328,7 +441,7 @@ func TestRootCmd_DryRun_ValidationFailed_EmptyDescription(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -357,7 +470,7 @@ func TestRootCmd_DryRun_ValidationFailed_OverheadWorkWithoutProjectId(t *testing
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -387,7 +500,7 @@ func TestRootCmd_DryRun_ValidationFailed_NegativeDuration(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22", "--dry-run"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -418,7 +531,7 @@ func TestRootCmd_ErrorLoggingProjectWork_ShouldNotStopSync(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -449,7 +562,7 @@ func TestRootCmd_ErrorLoggingOverheadWork_EntryWithoutProjectId_ShouldNotStopSyn
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -486,9 +599,9 @@ func TestRootCmd_ErrorLoggingOverheadWork_ShouldNotStopSync(t *testing.T) {
 	}
 
 	setupBasicConfig()
-	config.SetOverheadKey("testing", "ENG-1001")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "testing", Ticket: "ENG-1001"})
 
-	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -527,7 +640,7 @@ func TestRootCmd_LoggingOverheadWork_RequestOverheadKey(t *testing.T) {
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, inputCtrl, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, inputCtrl, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -567,7 +680,7 @@ func TestRootCmd_LoggingOverheadWork_RequestOverheadKey_ErrorPersistingConfig(t
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, inputCtrl, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, inputCtrl, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.NotNil(t, err)
@@ -606,7 +719,7 @@ func TestRootCmd_LoggingOverheadWork_ErrorRequestingOverheadKey_ShouldNotStopSyn
 
 	setupBasicConfig()
 
-	cmd := NewRootCmd(configManager, inputCtrl, togglAPI, jiraAPI)
+	cmd := NewRootCmd(configManager, inputCtrl, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
 	cmd.SetArgs([]string{"2020-05-22"})
 	err := cmd.Execute()
 	assert.Nil(t, err)
@@ -636,6 +749,11 @@ type MockTogglAPI struct {
 	TimeEntriesError error
 	Project          api.Project
 	ProjectError     error
+	Projects         []api.ProjectData
+	StoppedEntry     api.TimeEntry
+	StopError        error
+	Clients          []api.ClientData
+	ClientsError     error
 }
 
 func (mock MockTogglAPI) GetMe() (*api.Me, error) {
@@ -650,26 +768,97 @@ func (mock MockTogglAPI) GetProjectById(int) (*api.Project, error) {
 	return &mock.Project, mock.ProjectError
 }
 
+func (mock MockTogglAPI) GetProjects() ([]api.ProjectData, error) {
+	return mock.Projects, mock.ProjectError
+}
+
+func (mock MockTogglAPI) StopTimeEntry(int) (*api.TimeEntry, error) {
+	return &mock.StoppedEntry, mock.StopError
+}
+
+func (mock MockTogglAPI) GetClients() ([]api.ClientData, error) {
+	return mock.Clients, mock.ClientsError
+}
+
 type LoggedEntry struct {
 	Description string
 	Duration    time.Duration
 }
 
 type MockJiraAPI struct {
-	LoggedWork []LoggedEntry
-	APIError   error
+	LoggedWork      []LoggedEntry
+	UpdatedWork     map[string]LoggedEntry
+	DeletedWorklogs []string
+	Visibilities    map[string]api.WorklogVisibility
+	Myself          api.JiraUser
+	Worklogs        []api.JiraWorklog
+	UnknownKeys     []string
+	APIError        error
+	worklogCount    int
 }
 
-func (mock *MockJiraAPI) LogWork(description string, duration time.Duration) error {
+func (mock *MockJiraAPI) LogWork(description string, _ time.Time, duration time.Duration) error {
 	mock.trackLog(description, duration)
 	return mock.APIError
 }
 
-func (mock *MockJiraAPI) LogWorkWithUserDescription(_ string, duration time.Duration, description string) error {
+func (mock *MockJiraAPI) LogWorkWithUserDescription(_ string, _ time.Time, duration time.Duration, description string) error {
 	mock.trackLog(description, duration)
 	return mock.APIError
 }
 
+func (mock *MockJiraAPI) AddWorklog(ticket string, _ time.Time, duration time.Duration, description string, options api.WorklogOptions) (string, error) {
+	if description == "" {
+		description = ticket
+	}
+	mock.trackLog(description, duration)
+	mock.trackVisibility(ticket, options)
+	mock.worklogCount++
+	return fmt.Sprintf("%d", 10000+mock.worklogCount), mock.APIError
+}
+
+func (mock *MockJiraAPI) UpdateWorklog(ticket string, id string, duration time.Duration, description string, options api.WorklogOptions) error {
+	if description == "" {
+		description = ticket
+	}
+	mock.trackVisibility(ticket, options)
+	if mock.UpdatedWork == nil {
+		mock.UpdatedWork = make(map[string]LoggedEntry)
+	}
+	mock.UpdatedWork[id] = LoggedEntry{Description: description, Duration: duration}
+	return mock.APIError
+}
+
+func (mock *MockJiraAPI) DeleteWorklog(_ string, id string) error {
+	mock.DeletedWorklogs = append(mock.DeletedWorklogs, id)
+	return mock.APIError
+}
+
+func (mock *MockJiraAPI) GetMyself() (*api.JiraUser, error) {
+	return &mock.Myself, mock.APIError
+}
+
+func (mock *MockJiraAPI) GetWorklogs(time.Time) ([]api.JiraWorklog, error) {
+	return mock.Worklogs, mock.APIError
+}
+
+func (mock *MockJiraAPI) ProjectExists(key string) (bool, error) {
+	return mock.exists(key)
+}
+
+func (mock *MockJiraAPI) TicketExists(ticket string) (bool, error) {
+	return mock.exists(ticket)
+}
+
+func (mock *MockJiraAPI) exists(key string) (bool, error) {
+	for _, unknown := range mock.UnknownKeys {
+		if unknown == key {
+			return false, mock.APIError
+		}
+	}
+	return mock.APIError == nil, mock.APIError
+}
+
 func (mock *MockJiraAPI) trackLog(description string, duration time.Duration) {
 	mock.LoggedWork = append(mock.LoggedWork, LoggedEntry{
 		Description: description,
@@ -677,6 +866,16 @@ func (mock *MockJiraAPI) trackLog(description string, duration time.Duration) {
 	})
 }
 
+func (mock *MockJiraAPI) trackVisibility(ticket string, options api.WorklogOptions) {
+	if options.Visibility == nil {
+		return
+	}
+	if mock.Visibilities == nil {
+		mock.Visibilities = make(map[string]api.WorklogVisibility)
+	}
+	mock.Visibilities[ticket] = *options.Visibility
+}
+
 func (mock *MockJiraAPI) VerifyWorkLogged(description string, duration int) error {
 	expectedEntry := LoggedEntry{
 		Description: description,
@@ -703,6 +902,38 @@ func (mock *MockJiraAPI) VerifyNoOtherWorkLogged() error {
 	return fmt.Errorf("there were unexpected entries logged: %s\n", mock.LoggedWork)
 }
 
+type MockLedger struct {
+	Records   []ledger.Record
+	SaveError error
+}
+
+func (mock *MockLedger) Get(date string) (*ledger.Record, error) {
+	for i := range mock.Records {
+		if mock.Records[i].Date == date {
+			return &mock.Records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (mock *MockLedger) All() ([]ledger.Record, error) {
+	return mock.Records, nil
+}
+
+func (mock *MockLedger) Save(record ledger.Record) error {
+	if mock.SaveError != nil {
+		return mock.SaveError
+	}
+	for i := range mock.Records {
+		if mock.Records[i].Date == record.Date {
+			mock.Records[i] = record
+			return nil
+		}
+	}
+	mock.Records = append(mock.Records, record)
+	return nil
+}
+
 type RejectAllInputController struct {
 	t *testing.T
 }
@@ -721,19 +952,81 @@ type RejectAllCallsJiraAPI struct {
 	t *testing.T
 }
 
-func (mock RejectAllCallsJiraAPI) LogWork(string, time.Duration) (err error) {
+func (mock RejectAllCallsJiraAPI) LogWork(string, time.Time, time.Duration) (err error) {
+	mock.t.Fatal("no API should be called")
+	return
+}
+
+func (mock RejectAllCallsJiraAPI) LogWorkWithUserDescription(string, time.Time, time.Duration, string) (err error) {
 	mock.t.Fatal("no API should be called")
 	return
 }
 
-func (mock RejectAllCallsJiraAPI) LogWorkWithUserDescription(string, time.Duration, string) (err error) {
+func (mock RejectAllCallsJiraAPI) AddWorklog(string, time.Time, time.Duration, string, api.WorklogOptions) (id string, err error) {
 	mock.t.Fatal("no API should be called")
 	return
 }
 
+func (mock RejectAllCallsJiraAPI) UpdateWorklog(string, string, time.Duration, string, api.WorklogOptions) (err error) {
+	mock.t.Fatal("no API should be called")
+	return
+}
+
+func (mock RejectAllCallsJiraAPI) DeleteWorklog(string, string) (err error) {
+	mock.t.Fatal("no API should be called")
+	return
+}
+
+func (mock RejectAllCallsJiraAPI) GetMyself() (user *api.JiraUser, err error) {
+	mock.t.Fatal("no API should be called")
+	return
+}
+
+func (mock RejectAllCallsJiraAPI) GetWorklogs(time.Time) (worklogs []api.JiraWorklog, err error) {
+	mock.t.Fatal("no API should be called")
+	return
+}
+
+func (mock RejectAllCallsJiraAPI) ProjectExists(string) (exists bool, err error) {
+	mock.t.Fatal("no API should be called")
+	return
+}
+
+func (mock RejectAllCallsJiraAPI) TicketExists(string) (exists bool, err error) {
+	mock.t.Fatal("no API should be called")
+	return
+}
+
+func togglSources(togglAPI api.TogglAPI) map[string]source.TimeSource {
+	return map[string]source.TimeSource{
+		source.Toggl: togglAPI,
+	}
+}
+
+func jiraSinks(jiraAPI api.JiraAPI) map[string]sink.WorklogSink {
+	return map[string]sink.WorklogSink{
+		sink.Jira: sink.NewJiraSink(jiraAPI),
+	}
+}
+
+// captureLog returns what was logged while running the function
+func captureLog(run func()) string {
+	var output bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&output)
+	defer log.SetOutput(previous)
+
+	run()
+	return output.String()
+}
+
 func setupBasicConfig() {
 	config.Reset()
 	viper.SetConfigFile("test-config.yml")
+	setupProfileConfig()
+}
+
+func setupProfileConfig() {
 	config.Set(config.TogglServerURL, "http://localhost/toggl")
 	config.Set(config.TogglUsername, "TogglUser")
 	config.Set(config.TogglPassword, "TogglPassword")
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/source"
+)
+
+// Ways of handling running entries during a sync (see config.SyncRunning)
+const (
+	// runningFail fails the validation of the day, so nothing is synced until every entry is stopped
+	runningFail = "fail"
+	// runningSkip leaves running entries out with a warning, and keeps the day pending so they are synced once stopped
+	runningSkip = "skip"
+	// runningStopped syncs stopped entries only, ignoring running ones for good
+	runningStopped = "stopped"
+	// runningPartial counts the time of running entries up to now, updating their worklogs on later runs
+	runningPartial = "partial"
+)
+
+var runningModes = []string{runningFail, runningSkip, runningStopped, runningPartial}
+
+// runningMode returns the way of handling running entries requested (--running), or the one in config (failing by default)
+func runningMode(requested string) (string, error) {
+	mode := requested
+	if mode == "" {
+		mode = configOrDefault(config.SyncRunning, runningFail)
+	}
+	if !contains(runningModes, mode) {
+		return "", fmt.Errorf("unknown way of handling running entries [%s]; available ones: fail, skip, stopped or partial", mode)
+	}
+	return mode, nil
+}
+
+// handleRunningEntries stops running entries (if requested), and then handles those still running following the mode.
+// It returns the entries to sync, and whether the day must stay pending to sync the time of running entries later.
+func handleRunningEntries(timeSource source.TimeSource, entries []api.TimeEntry, opts syncOptions, now time.Time) ([]api.TimeEntry, bool, error) {
+	mode, err := runningMode(opts.running)
+	if err != nil {
+		return nil, false, err
+	}
+
+	handled := make([]api.TimeEntry, 0, len(entries))
+	pending := false
+	for _, entry := range entries {
+		if entry.Duration >= 0 {
+			handled = append(handled, entry)
+			continue
+		}
+
+		if opts.stopRunning {
+			stopped, err := stopRunningEntry(opts.logger, timeSource, entry, opts.dryRun)
+			if err != nil {
+				return nil, false, err
+			} else if stopped != nil {
+				handled = append(handled, *stopped)
+				continue
+			}
+		}
+
+		switch mode {
+		case runningFail:
+			handled = append(handled, entry)
+		case runningSkip:
+			opts.logger.Printf("Skipping entry [%s]; it is still running (it will be synced once stopped)", entry.Description)
+			pending = true
+		case runningStopped:
+			opts.logger.Printf("Ignoring entry [%s]; it is still running", entry.Description)
+		case runningPartial:
+			entry.Duration = int(now.Sub(entry.Start).Seconds())
+			entry.Running = true
+			opts.logger.Printf("Entry [%s] is still running; counting [%d]s so far (its worklog is updated on later runs)", entry.Description, entry.Duration)
+			handled = append(handled, entry)
+			pending = true
+		}
+	}
+	return handled, pending, nil
+}
+
+// stopRunningEntry stops the timer of the entry, returning the stopped entry (nil on a dry-run)
+func stopRunningEntry(logger *log.Logger, timeSource source.TimeSource, entry api.TimeEntry, dryRun bool) (*api.TimeEntry, error) {
+	stopper, ok := timeSource.(source.TimerStopper)
+	if !ok {
+		return nil, fmt.Errorf("unable to stop entry [%s]; the time entry source cannot stop running timers", entry.Description)
+	}
+	if dryRun {
+		logger.Printf("Stopping running entry [%s]... SKIPPED! (dry-run)", entry.Description)
+		return nil, nil
+	}
+
+	stopped, err := stopper.StopTimeEntry(entry.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error stopping running entry [%s]: %s", entry.Description, err)
+	}
+	logger.Printf("Stopped running entry [%s] ([%d]s tracked)", stopped.Description, stopped.Duration)
+	return stopped, nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func runningTestEntries() []api.TimeEntry {
+	return []api.TimeEntry{
+		{Id: 1, Duration: 240, Description: "ENG-1001"},
+		{Id: 2, Start: time.Now().Add(-30 * time.Minute), Duration: -1590000000, Description: "ENG-1002"},
+	}
+}
+
+func TestRootCmd_RunningEntries_FailByDefault(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{TimeEntries: runningTestEntries()}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "validation failed")
+}
+
+func TestRootCmd_RunningEntries_Skip(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncRunning, runningSkip)
+	togglAPI := &MockTogglAPI{TimeEntries: runningTestEntries()}
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+	// The day stays pending, so the running entry is synced once stopped
+	assert.False(t, syncLedger.Records[0].Complete)
+}
+
+func TestRootCmd_RunningEntries_SkipThenSyncOnceStopped(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncRunning, runningSkip)
+	togglAPI := &MockTogglAPI{TimeEntries: []api.TimeEntry{
+		{Id: 1, Duration: 240, Description: "ENG-1001"},
+		{Id: 2, Start: time.Now().Add(-30 * time.Minute), Duration: -1590000000, Description: "ENG-1001"},
+	}}
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	assert.Nil(t, cmd.Execute())
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+
+	// Once stopped, the entry is merged with the one already logged, and only its time is logged
+	togglAPI.TimeEntries[1].Duration = 1800
+	cmd = NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	assert.Nil(t, cmd.Execute())
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 1800))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+
+	record := syncLedger.Records[0]
+	assert.True(t, record.Complete)
+	assert.Equal(t, []ledger.Worklog{
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}},
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 1800, EntryIDs: []int{1, 2}},
+	}, record.Worklogs)
+}
+
+func TestRootCmd_RunningEntries_StoppedOnly(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{TimeEntries: runningTestEntries()}
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22", "--running", runningStopped})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+	assert.True(t, syncLedger.Records[0].Complete)
+}
+
+func TestRootCmd_RunningEntries_Partial(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncRunning, runningPartial)
+	togglAPI := &MockTogglAPI{TimeEntries: runningTestEntries()}
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	record := syncLedger.Records[0]
+	assert.False(t, record.Complete)
+	partial, ok := record.Find("jira", "ENG-1002", "ENG-1002")
+	assert.True(t, ok)
+	assert.True(t, partial.Partial)
+	assert.Equal(t, "10001", partial.WorklogID)
+	assert.InDelta(t, 1800, partial.Seconds, 10)
+
+	// Once stopped, the partial worklog is updated with the final duration
+	togglAPI.TimeEntries[1].Duration = 2400
+	cmd = NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), syncLedger)
+	cmd.SetArgs([]string{"2020-05-22"})
+	err = cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Equal(t, LoggedEntry{Description: "ENG-1002", Duration: 2400 * time.Second}, jiraAPI.UpdatedWork["10001"])
+	record = syncLedger.Records[0]
+	assert.True(t, record.Complete)
+	assert.Equal(t, []ledger.Worklog{
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}},
+		{Sink: "jira", Ticket: "ENG-1002", Description: "ENG-1002", Seconds: 2400, EntryIDs: []int{2}, WorklogID: "10001"},
+	}, record.Worklogs)
+}
+
+func TestRootCmd_RunningEntries_StopRunning(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{
+		TimeEntries:  runningTestEntries(),
+		StoppedEntry: api.TimeEntry{Id: 2, Duration: 1800, Description: "ENG-1002"},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--stop-running"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1002", 1800))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestRootCmd_RunningEntries_ErrorStopping(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{TimeEntries: runningTestEntries(), StopError: errors.New("stub error")}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--stop-running"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "error stopping running entry [ENG-1002]: stub error")
+}
+
+func TestRootCmd_RunningEntries_UnknownMode(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--running", "ignore"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "unknown way of handling running entries [ignore]; available ones: fail, skip, stopped or partial")
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/signal"
+	"strings"
+	stdsync "sync"
+	"syscall"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWebhookListenAddress = ":8080"
+	togglWebhookPath            = "/webhooks/toggl"
+	togglSignatureHeader        = "X-Webhook-Signature-256"
+)
+
+// NewServeCmd creates a new Cobra Command that receives Toggl webhooks and logs work as soon as entries are stopped
+func NewServeCmd(configManager config.Manager, togglSource source.TimeSource, jiraAPI api.JiraAPI, syncLedger ledger.Ledger) *cobra.Command {
+	var listenAddress string
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Receive Toggl webhooks and log work as soon as time entries are stopped",
+		Long: "Receive Toggl webhooks (on " + togglWebhookPath + ") and log work on Jira as soon as time entries are stopped. " +
+			"Worklogs are updated (or deleted) when their time entries are edited (or deleted) in Toggl.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+			if config.Get(config.TogglWebhookSecret) == "" {
+				return fmt.Errorf("toggl webhook secret is required to verify incoming requests (set %s)", config.TogglWebhookSecret)
+			}
+			if err := validateServeConfig(); err != nil {
+				return err
+			}
+			if listenAddress == "" {
+				listenAddress = configOrDefault(config.WebhookListenAddress, defaultWebhookListenAddress)
+			}
+
+			location, err := userLocation(togglSource, timezoneFlag(cmd))
+			if err != nil {
+				return err
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle(togglWebhookPath, newWebhookHandler(togglSource, jiraAPI, syncLedger, location))
+			server := &http.Server{Addr: listenAddress, Handler: mux}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				_ = server.Shutdown(context.Background())
+			}()
+
+			log.Printf("Listening for Toggl webhooks on %s%s", listenAddress, togglWebhookPath)
+			if err := server.ListenAndServe(); err != http.ErrServerClosed {
+				return err
+			}
+			log.Print("Server stopped")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&listenAddress, "listen", "", fmt.Sprintf("address to listen on (default \"%s\")", defaultWebhookListenAddress))
+	return cmd
+}
+
+// validateServeConfig checks the config keys serve needs: work is only read from Toggl and logged on Jira,
+// whatever the sinks selected for syncs (see missingConfig)
+func validateServeConfig() error {
+	missing := missingKeys(config.JiraProjectKey, config.JiraServerURL, config.JiraUsername, config.JiraPassword)
+	return validateKeys(append(missing, missingSourcesConfig([]string{source.Toggl})...))
+}
+
+type togglWebhookEvent struct {
+	Metadata       togglWebhookMetadata `json:"metadata"`
+	Payload        json.RawMessage      `json:"payload"`
+	ValidationCode string               `json:"validation_code"`
+}
+
+type togglWebhookMetadata struct {
+	Action string `json:"action"`
+	Model  string `json:"model"`
+}
+
+type togglWebhookTimeEntry struct {
+	ID          int        `json:"id"`
+	ProjectID   int        `json:"project_id"`
+	Description string     `json:"description"`
+	Start       time.Time  `json:"start"`
+	Stop        *time.Time `json:"stop"`
+	Duration    int        `json:"duration"`
+	Tags        []string   `json:"tags"`
+	Billable    bool       `json:"billable"`
+}
+
+// webhookHandler logs work on Jira for every Toggl time entry notified through a webhook.
+// Requests are handled one at a time, as they all read and write the same ledger.
+type webhookHandler struct {
+	timeSource source.TimeSource
+	jiraAPI    api.JiraAPI
+	syncLedger ledger.Ledger
+	location   *time.Location
+	mutex      stdsync.Mutex
+}
+
+// newWebhookHandler creates the handler; entries are synced on the date they started, in the location provided
+func newWebhookHandler(timeSource source.TimeSource, jiraAPI api.JiraAPI, syncLedger ledger.Ledger, location *time.Location) http.Handler {
+	return &webhookHandler{
+		timeSource: timeSource,
+		jiraAPI:    jiraAPI,
+		syncLedger: syncLedger,
+		location:   location,
+	}
+}
+
+func (handler *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading request body", http.StatusBadRequest)
+		return
+	}
+	if !validSignature(body, r.Header.Get(togglSignatureHeader)) {
+		log.Print("Rejecting webhook request with an invalid signature")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event togglWebhookEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		http.Error(w, fmt.Sprintf("error unmarshalling event: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if event.ValidationCode != "" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"validation_code": event.ValidationCode})
+		return
+	}
+	if event.Metadata.Model != "time_entry" {
+		_, _ = fmt.Fprintln(w, "ignored")
+		return
+	}
+
+	var togglEntry togglWebhookTimeEntry
+	if err = json.Unmarshal(event.Payload, &togglEntry); err != nil {
+		http.Error(w, fmt.Sprintf("error unmarshalling time entry: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if event.Metadata.Action == "deleted" {
+		err = handler.deleteWorklog(togglEntry.ID)
+	} else {
+		err = handler.pushWorklog(togglEntry)
+	}
+	if err != nil {
+		log.Printf("Webhook for time entry [%d] failed with an error: %s", togglEntry.ID, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// validSignature verifies the HMAC-SHA256 signature of the body (sent by Toggl as "sha256=<hex digest>")
+func validSignature(body []byte, signature string) bool {
+	secret := config.Get(config.TogglWebhookSecret)
+	if secret == "" || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(received, mac.Sum(nil))
+}
+
+// pushWorklog logs the work of a stopped time entry on Jira, updating the worklog previously created for it (if any).
+// Entries excluded from syncs (see excludeEntries) are not logged, and the worklog previously created for them is deleted.
+func (handler *webhookHandler) pushWorklog(togglEntry togglWebhookTimeEntry) error {
+	if togglEntry.Stop == nil || togglEntry.Duration < 0 {
+		log.Printf("Ignoring time entry [%d]; it is still running", togglEntry.ID)
+		return nil
+	}
+
+	entry := api.TimeEntry{
+		Id:          togglEntry.ID,
+		Pid:         togglEntry.ProjectID,
+		Start:       togglEntry.Start,
+		Stop:        *togglEntry.Stop,
+		Duration:    togglEntry.Duration,
+		Description: togglEntry.Description,
+		Tags:        togglEntry.Tags,
+		Billable:    togglEntry.Billable,
+	}
+	projects := newProjectCache()
+	_, excluded, err := excludeEntries(handler.timeSource, projects, []api.TimeEntry{entry}, entryFilters{})
+	if err != nil {
+		return err
+	} else if len(excluded) != 0 {
+		log.Printf("Ignoring time entry [%d]; excluded by rule %s", entry.Id, excluded[0].Reason)
+		return handler.deleteWorklog(entry.Id)
+	}
+	if ok, message := validateEntry(entry); !ok {
+		log.Printf("Ignoring time entry [%d]; %s", entry.Id, strings.TrimSpace(message))
+		return nil
+	}
+
+	syncDate := entry.Start.In(handler.location).Format("2006-01-02")
+	worklog, err := createWorklog(unattendedInputController{}, handler.timeSource, projects, syncDate, handler.location, entry)
+	if err != nil {
+		log.Printf("Ignoring time entry [%d]; %s", entry.Id, err)
+		return nil
+	}
+
+	previous, idx, err := handler.findEntry(entry.Id)
+	if err != nil {
+		return err
+	}
+	if previous != nil && !editable(previous.Worklogs[idx]) {
+		log.Printf("Time entry [%d] was logged on Jira %s; logging the time tracked since instead", entry.Id, notEditableReason(previous.Worklogs[idx]))
+	} else if previous != nil {
+		synced := previous.Worklogs[idx]
+		if previous.Date == syncDate && synced.Ticket == worklog.Ticket {
+			return handler.updateWorklog(previous, idx, worklog)
+		}
+		if err = handler.removeWorklog(previous, idx); err != nil {
+			return err
+		}
+	}
+
+	record, err := handler.syncLedger.Get(syncDate)
+	if err != nil {
+		return err
+	} else if record == nil {
+		record = &ledger.Record{Date: syncDate}
+	}
+	// Like syncs do, only the time not logged yet is logged (see logWork)
+	logged := 0
+	for _, synced := range record.Synced(sink.Jira, worklog.Ticket, worklog.Description, []int{entry.Id}) {
+		logged += synced.Seconds
+	}
+	worklog.TimeSpent -= time.Duration(logged) * time.Second
+	if worklog.TimeSpent <= 0 {
+		log.Printf("Skipping time entry [%d]; [%d]s were already logged for [%s]", entry.Id, logged, worklog.Description)
+		return nil
+	}
+
+	worklogID, err := handler.jiraAPI.AddWorklog(worklog.Ticket, worklog.Started, worklog.TimeSpent, sink.JiraComment(worklog), sink.JiraWorklogOptions(worklog))
+	if err != nil {
+		return err
+	}
+	log.Printf("Successfully logged [%d]s on %s for entry [%s]", int(worklog.TimeSpent.Seconds()), worklog.Ticket, worklog.Description)
+
+	record.SyncedAt = time.Now()
+	record.Worklogs = append(record.Worklogs, ledger.Worklog{
+		Sink:        sink.Jira,
+		Ticket:      worklog.Ticket,
+		Description: worklog.Description,
+		Seconds:     int(worklog.TimeSpent.Seconds()),
+		EntryIDs:    []int{entry.Id},
+		WorklogID:   worklogID,
+	})
+	return handler.syncLedger.Save(*record)
+}
+
+func (handler *webhookHandler) updateWorklog(record *ledger.Record, idx int, worklog sink.Worklog) error {
+	synced := &record.Worklogs[idx]
+	err := handler.jiraAPI.UpdateWorklog(worklog.Ticket, synced.WorklogID, worklog.TimeSpent, sink.JiraComment(worklog), sink.JiraWorklogOptions(worklog))
+	if err != nil {
+		return err
+	}
+	log.Printf("Successfully updated worklog [%s] on %s for entry [%s]", synced.WorklogID, worklog.Ticket, worklog.Description)
+
+	synced.Description = worklog.Description
+	synced.Seconds = int(worklog.TimeSpent.Seconds())
+	record.SyncedAt = time.Now()
+	return handler.syncLedger.Save(*record)
+}
+
+// deleteWorklog deletes the worklog created for a time entry (if any, and if it can be deleted in place)
+func (handler *webhookHandler) deleteWorklog(entryID int) error {
+	record, idx, err := handler.findEntry(entryID)
+	if err != nil || record == nil {
+		return err
+	} else if !editable(record.Worklogs[idx]) {
+		log.Printf("Keeping worklog on %s for time entry [%d]; it was logged %s", record.Worklogs[idx].Ticket, entryID, notEditableReason(record.Worklogs[idx]))
+		return nil
+	}
+	return handler.removeWorklog(record, idx)
+}
+
+// editable tells whether the worklog can be updated (or deleted) in place when its time entry changes: its Jira id must be
+// known (syncs only keep it for partial worklogs), and it must not include the time of other entries (e.g. summarized ones)
+func editable(worklog ledger.Worklog) bool {
+	return worklog.WorklogID != "" && len(worklog.EntryIDs) == 1
+}
+
+func notEditableReason(worklog ledger.Worklog) string {
+	if len(worklog.EntryIDs) > 1 {
+		return fmt.Sprintf("along with other entries %v", worklog.EntryIDs)
+	}
+	return "without keeping the id of the worklog"
+}
+
+func (handler *webhookHandler) removeWorklog(record *ledger.Record, idx int) error {
+	synced := record.Worklogs[idx]
+	if err := handler.jiraAPI.DeleteWorklog(synced.Ticket, synced.WorklogID); err != nil {
+		return err
+	}
+	log.Printf("Successfully deleted worklog [%s] on %s for entry [%s]", synced.WorklogID, synced.Ticket, synced.Description)
+
+	record.Worklogs = append(record.Worklogs[:idx], record.Worklogs[idx+1:]...)
+	record.Complete = false
+	return handler.syncLedger.Save(*record)
+}
+
+// findEntry returns the ledger record containing the Jira worklog created for a time entry (nil if there is none)
+func (handler *webhookHandler) findEntry(entryID int) (*ledger.Record, int, error) {
+	records, err := handler.syncLedger.All()
+	if err != nil {
+		return nil, -1, err
+	}
+	for i := range records {
+		if idx := records[i].FindEntry(sink.Jira, entryID); idx >= 0 {
+			return &records[i], idx, nil
+		}
+	}
+	return nil, -1, nil
+}
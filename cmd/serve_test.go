@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/stretchr/testify/assert"
+)
+
+const webhookTestSecret = "s3cr3t"
+
+func TestServeCmd_SecretIsRequired(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewServeCmd(&MockConfigManager{InitOk: true}, &MockTogglAPI{}, &RejectAllCallsJiraAPI{t: t}, &MockLedger{})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestValidateServeConfig_OnlyJiraAndToggl(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncSinks, []string{sink.Jira, sink.CSV})
+	assert.Nil(t, validateServeConfig())
+
+	config.Unset(config.JiraPassword)
+	assert.EqualError(t, validateServeConfig(), "configuration file is invalid (missing jira.password)! Please, run 'configure' to create a new configuration file")
+}
+
+func TestWebhook_InvalidSignature(t *testing.T) {
+	setupWebhookConfig()
+
+	handler := newWebhookHandler(&MockTogglAPI{}, &RejectAllCallsJiraAPI{t: t}, &MockLedger{}, time.UTC)
+	body := timeEntryEvent("created", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240}`)
+	req := httptest.NewRequest(http.MethodPost, togglWebhookPath, strings.NewReader(body))
+	req.Header.Set(togglSignatureHeader, "sha256=0123456789abcdef")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestWebhook_Ping(t *testing.T) {
+	setupWebhookConfig()
+
+	handler := newWebhookHandler(&MockTogglAPI{}, &RejectAllCallsJiraAPI{t: t}, &MockLedger{}, time.UTC)
+	recorder := sendWebhook(handler, `{"payload": "ping", "validation_code": "abc-123"}`)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"validation_code": "abc-123"}`, recorder.Body.String())
+}
+
+func TestWebhook_StoppedEntryIsLogged(t *testing.T) {
+	setupWebhookConfig()
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{}
+
+	handler := newWebhookHandler(&MockTogglAPI{}, jiraAPI, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("updated", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+	record, _ := syncLedger.Get(time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC).Local().Format("2006-01-02"))
+	if assert.NotNil(t, record) {
+		assert.Equal(t, []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}, WorklogID: "10001"}}, record.Worklogs)
+		assert.False(t, record.Complete)
+	}
+}
+
+func TestWebhook_OverheadEntryIsLogged(t *testing.T) {
+	setupWebhookConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+	togglAPI := &MockTogglAPI{Project: api.Project{Data: api.ProjectData{Id: 7, Name: "Meetings"}}}
+	jiraAPI := &MockJiraAPI{}
+
+	handler := newWebhookHandler(togglAPI, jiraAPI, &MockLedger{}, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("created", `{"id": 1, "project_id": 7, "description": "Team catch-up", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:30:00Z", "duration": 1800}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("Team catch-up", 1800))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestWebhook_EditedEntryIsUpdated(t *testing.T) {
+	setupWebhookConfig()
+	date := time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC).Local().Format("2006-01-02")
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     date,
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}, WorklogID: "10042"}},
+	}}}
+
+	handler := newWebhookHandler(&MockTogglAPI{}, jiraAPI, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("updated", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:10:00Z", "duration": 600}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+	assert.Equal(t, map[string]LoggedEntry{"10042": {Description: "ENG-1001", Duration: 600 * time.Second}}, jiraAPI.UpdatedWork)
+	record, _ := syncLedger.Get(date)
+	assert.Equal(t, 600, record.Worklogs[0].Seconds)
+}
+
+func TestWebhook_TicketChangeReplacesWorklog(t *testing.T) {
+	setupWebhookConfig()
+	date := time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC).Local().Format("2006-01-02")
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     date,
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}, WorklogID: "10042"}},
+	}}}
+
+	handler := newWebhookHandler(&MockTogglAPI{}, jiraAPI, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("updated", `{"id": 1, "description": "ENG-1002", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"10042"}, jiraAPI.DeletedWorklogs)
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1002", 240))
+	record, _ := syncLedger.Get(date)
+	assert.Equal(t, []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1002", Description: "ENG-1002", Seconds: 240, EntryIDs: []int{1}, WorklogID: "10001"}}, record.Worklogs)
+}
+
+func TestWebhook_DeletedEntryIsDeleted(t *testing.T) {
+	setupWebhookConfig()
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     "2020-05-22",
+		Complete: true,
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}, WorklogID: "10042"}},
+	}}}
+
+	handler := newWebhookHandler(&MockTogglAPI{}, jiraAPI, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("deleted", `{"id": 1}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"10042"}, jiraAPI.DeletedWorklogs)
+	record, _ := syncLedger.Get("2020-05-22")
+	assert.Empty(t, record.Worklogs)
+	assert.False(t, record.Complete)
+}
+
+func TestWebhook_EntrySyncedWithoutWorklogID(t *testing.T) {
+	setupWebhookConfig()
+	date := time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC).Local().Format("2006-01-02")
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     date,
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}}},
+	}}}
+
+	// The worklog cannot be updated, so only the time tracked since is logged
+	handler := newWebhookHandler(&MockTogglAPI{}, jiraAPI, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("updated", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:10:00Z", "duration": 600}`))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, jiraAPI.UpdatedWork)
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 360))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+
+	// Nor deleted
+	recorder = sendWebhook(handler, timeEntryEvent("deleted", `{"id": 1}`))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, jiraAPI.DeletedWorklogs)
+	record, _ := syncLedger.Get(date)
+	assert.Equal(t, []ledger.Worklog{
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}},
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 360, EntryIDs: []int{1}, WorklogID: "10001"},
+	}, record.Worklogs)
+}
+
+func TestWebhook_EntryOfMergedWorklog(t *testing.T) {
+	setupWebhookConfig()
+	date := time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC).Local().Format("2006-01-02")
+	jiraAPI := &MockJiraAPI{}
+	merged := ledger.Worklog{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 900, EntryIDs: []int{1, 2, 3}, WorklogID: "10042"}
+	syncLedger := &MockLedger{Records: []ledger.Record{{Date: date, Worklogs: []ledger.Worklog{merged}}}}
+
+	// The merged worklog keeps the time of the other entries
+	handler := newWebhookHandler(&MockTogglAPI{}, jiraAPI, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("updated", `{"id": 2, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:10:00Z", "duration": 600}`))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, jiraAPI.UpdatedWork)
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+
+	recorder = sendWebhook(handler, timeEntryEvent("deleted", `{"id": 2}`))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, jiraAPI.DeletedWorklogs)
+	record, _ := syncLedger.Get(date)
+	assert.Equal(t, []ledger.Worklog{merged}, record.Worklogs)
+}
+
+func TestWebhook_RunningEntryIsIgnored(t *testing.T) {
+	setupWebhookConfig()
+
+	handler := newWebhookHandler(&MockTogglAPI{}, &RejectAllCallsJiraAPI{t: t}, &MockLedger{}, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("created", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": null, "duration": -1590138000}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWebhook_InvalidEntryIsIgnored(t *testing.T) {
+	setupWebhookConfig()
+
+	handler := newWebhookHandler(&MockTogglAPI{}, &RejectAllCallsJiraAPI{t: t}, &MockLedger{}, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("created", `{"id": 1, "description": "Unassigned work", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWebhook_EntryAlreadySyncedIsSkipped(t *testing.T) {
+	setupWebhookConfig()
+	date := time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC).Local().Format("2006-01-02")
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     date,
+		Complete: true,
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240}},
+	}}}
+
+	handler := newWebhookHandler(&MockTogglAPI{}, &RejectAllCallsJiraAPI{t: t}, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("updated", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWebhook_ExcludedEntryIsIgnored(t *testing.T) {
+	setupWebhookConfig()
+	config.Set(config.SyncExcludeTags, "no-sync")
+	config.Set(config.SyncExcludeBillable, "true")
+
+	handler := newWebhookHandler(&MockTogglAPI{}, &RejectAllCallsJiraAPI{t: t}, &MockLedger{}, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("created", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240, "tags": ["no-sync"]}`))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = sendWebhook(handler, timeEntryEvent("created", `{"id": 2, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240, "billable": true}`))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWebhook_EntryExcludedOnceSyncedIsDeleted(t *testing.T) {
+	setupWebhookConfig()
+	config.Set(config.SyncExcludeTags, "no-sync")
+	jiraAPI := &MockJiraAPI{}
+	syncLedger := &MockLedger{Records: []ledger.Record{{
+		Date:     "2020-05-22",
+		Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 240, EntryIDs: []int{1}, WorklogID: "10042"}},
+	}}}
+
+	handler := newWebhookHandler(&MockTogglAPI{}, jiraAPI, syncLedger, time.UTC)
+	recorder := sendWebhook(handler, timeEntryEvent("updated", `{"id": 1, "description": "ENG-1001", "start": "2020-05-22T09:00:00Z", "stop": "2020-05-22T09:04:00Z", "duration": 240, "tags": ["no-sync"]}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"10042"}, jiraAPI.DeletedWorklogs)
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func setupWebhookConfig() {
+	setupBasicConfig()
+	config.Set(config.TogglWebhookSecret, webhookTestSecret)
+}
+
+func timeEntryEvent(action string, payload string) string {
+	return `{"metadata": {"action": "` + action + `", "model": "time_entry"}, "payload": ` + payload + `}`
+}
+
+func sendWebhook(handler http.Handler, body string) *httptest.ResponseRecorder {
+	mac := hmac.New(sha256.New, []byte(webhookTestSecret))
+	mac.Write([]byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, togglWebhookPath, strings.NewReader(body))
+	req.Header.Set(togglSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
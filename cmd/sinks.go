@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/sink"
+)
+
+// configuredSinks returns the names of the sinks selected in config (Jira, if none is selected)
+func configuredSinks() []string {
+	names := config.GetSlice(config.SyncSinks)
+	if len(names) == 0 {
+		return []string{sink.Jira}
+	}
+	return names
+}
+
+func selectSinks(sinks map[string]sink.WorklogSink) ([]string, error) {
+	names := configuredSinks()
+	for _, name := range names {
+		if _, ok := sinks[name]; !ok {
+			return nil, fmt.Errorf("unknown sink [%s] found in configuration", name)
+		}
+	}
+	return names, nil
+}
+
+// missingSinksConfig returns the config keys required by the selected sinks that have no value
+func missingSinksConfig() []string {
+	var missing []string
+	for _, name := range configuredSinks() {
+		switch name {
+		case sink.Jira:
+			missing = append(missing, missingJiraConfig()...)
+		case sink.CSV:
+			missing = append(missing, missingKeys(config.CSVSinkPath)...)
+		case sink.JSON:
+			missing = append(missing, missingKeys(config.JSONSinkPath)...)
+		case sink.Tempo:
+			missing = append(missing, missingKeys(config.TempoToken, config.TempoAccountID)...)
+		}
+	}
+	return missing
+}
+
+func missingJiraConfig() []string {
+	missing := missingKeys(config.JiraServerURL, config.JiraUsername, config.JiraPassword)
+	for _, instance := range config.GetJiraInstances() {
+		missing = append(missing, missingKeys(
+			config.JiraInstanceKey(instance, config.JiraServerURL),
+			config.JiraInstanceKey(instance, config.JiraUsername),
+			config.JiraInstanceKey(instance, config.JiraPassword),
+			config.JiraInstanceKey(instance, config.JiraProjectKey),
+		)...)
+	}
+	return missing
+}
+
+// missingKeys returns the keys (out of those provided) without a value in config
+func missingKeys(keys ...string) []string {
+	var missing []string
+	for _, key := range keys {
+		if config.Get(key) == "" && len(config.GetSlice(key)) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCmd_MultipleSinks(t *testing.T) {
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{
+				Id:          1,
+				Duration:    240,
+				Description: "ENG-1001",
+			},
+		},
+	}
+	jiraAPI := &MockJiraAPI{}
+	fileSink := &MockSink{}
+
+	setupBasicConfig()
+	config.Set(config.SyncSinks, []string{sink.Jira, sink.CSV})
+	config.Set(config.CSVSinkPath, "/tmp/timesheet.csv")
+	config.Set(config.SyncTimezone, "UTC")
+
+	sinks := jiraSinks(jiraAPI)
+	sinks[sink.CSV] = fileSink
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), sinks, &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Equal(t, []sink.Worklog{{Date: "2020-05-22", Started: time.Date(2020, 5, 22, 0, 0, 0, 0, time.UTC), Ticket: "ENG-1001", TimeSpent: 240 * time.Second, Description: "ENG-1001"}}, fileSink.Worklogs)
+}
+
+func TestRootCmd_FileSinkOnly_JiraCredentialsNotRequired(t *testing.T) {
+	fileSink := &MockSink{}
+
+	setupBasicConfig()
+	config.Set(config.JiraServerURL, "")
+	config.Set(config.JiraPassword, "")
+	config.Set(config.SyncSinks, []string{sink.JSON})
+	config.Set(config.JSONSinkPath, "/tmp/timesheet.json")
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), map[string]sink.WorklogSink{sink.JSON: fileSink}, &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+}
+
+func TestRootCmd_FileSinkWithoutPath(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncSinks, []string{sink.CSV})
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), map[string]sink.WorklogSink{sink.CSV: &MockSink{}}, &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestRootCmd_TempoSinkWithoutCredentials(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncSinks, []string{sink.Tempo})
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), map[string]sink.WorklogSink{sink.Tempo: &MockSink{}}, &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestRootCmd_WorklogsCarryBillableFlag(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncSinks, []string{sink.Tempo})
+	config.Set(config.TempoToken, "tempo-token")
+	config.Set(config.TempoAccountID, "account-1234")
+	togglAPI := &MockTogglAPI{TimeEntries: []api.TimeEntry{
+		{Id: 1, Duration: 240, Description: "ENG-1001", Billable: true},
+		{Id: 2, Duration: 60, Description: "ENG-1002"},
+	}}
+	tempoSink := &MockSink{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), map[string]sink.WorklogSink{sink.Tempo: tempoSink}, &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	assert.Nil(t, cmd.Execute())
+
+	assert.Len(t, tempoSink.Worklogs, 2)
+	assert.True(t, tempoSink.Worklogs[0].Billable)
+	assert.False(t, tempoSink.Worklogs[1].Billable)
+}
+
+func TestRootCmd_UnknownSink(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncSinks, []string{"carrier-pigeon"})
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+type MockSink struct {
+	Worklogs []sink.Worklog
+	Error    error
+}
+
+func (mock *MockSink) Write(worklog sink.Worklog) error {
+	mock.Worklogs = append(mock.Worklogs, worklog)
+	return mock.Error
+}
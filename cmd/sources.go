@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/source"
+)
+
+// configuredSources returns the names of the sources requested for this run,
+// falling back to the ones selected in config (Toggl, if none is selected)
+func configuredSources(requested []string) []string {
+	if len(requested) != 0 {
+		return requested
+	}
+
+	names := config.GetSlice(config.SyncSources)
+	if len(names) == 0 {
+		return []string{source.Toggl}
+	}
+	return names
+}
+
+func selectSources(sources map[string]source.TimeSource, names []string) (source.TimeSource, error) {
+	selected := make([]source.TimeSource, 0, len(names))
+	for _, name := range names {
+		timeSource, ok := sources[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown time entry source [%s]", name)
+		}
+		selected = append(selected, timeSource)
+	}
+
+	if len(selected) == 1 {
+		return selected[0], nil
+	}
+	return source.NewMultiSource(selected...), nil
+}
+
+// missingSourcesConfig returns the config keys required by the sources that have no value
+func missingSourcesConfig(names []string) []string {
+	var missing []string
+	for _, name := range names {
+		switch name {
+		case source.Toggl:
+			missing = append(missing, missingKeys(config.TogglServerURL, config.TogglUsername, config.TogglPassword)...)
+		case source.Clockify:
+			missing = append(missing, missingKeys(config.ClockifyAPIKey, config.ClockifyWorkspaceID)...)
+		case source.CSV:
+			missing = append(missing, missingKeys(config.CSVSourcePath)...)
+		case source.ICS:
+			missing = append(missing, missingKeys(config.ICSSourcePath)...)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCmd_MultipleSources(t *testing.T) {
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{
+				Id:          1,
+				Duration:    240,
+				Description: "ENG-1001",
+			},
+		},
+	}
+	calendar := &MockTimeSource{
+		TimeEntries: []api.TimeEntry{
+			{
+				Id:          2,
+				Pid:         20,
+				Duration:    1800,
+				Description: "Team catch-up",
+			},
+		},
+		ProjectName: "Meetings",
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	setupBasicConfig()
+	config.Set(config.ICSSourcePath, "/tmp/calendar.ics")
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+
+	sources := togglSources(togglAPI)
+	sources[source.ICS] = calendar
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, sources, jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--source", "toggl,ics"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.NoError(t, jiraAPI.VerifyWorkLogged("Team catch-up", 1800))
+	assert.NoError(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestRootCmd_SourcesFromConfig(t *testing.T) {
+	calendar := &MockTimeSource{}
+
+	setupBasicConfig()
+	config.Set(config.SyncSources, []string{source.ICS})
+	config.Set(config.ICSSourcePath, "/tmp/calendar.ics")
+
+	sources := map[string]source.TimeSource{source.ICS: calendar}
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, sources, jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.True(t, calendar.Called)
+}
+
+func TestRootCmd_ClockifySourceWithoutCredentials(t *testing.T) {
+	setupBasicConfig()
+
+	sources := map[string]source.TimeSource{source.Clockify: &MockTimeSource{}}
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, sources, jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--source", "clockify"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestRootCmd_UnknownSource(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--source", "sundial"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+type MockTimeSource struct {
+	TimeEntries []api.TimeEntry
+	ProjectName string
+	Called      bool
+}
+
+func (mock *MockTimeSource) GetTimeEntries(time.Time, time.Time) ([]api.TimeEntry, error) {
+	mock.Called = true
+	return mock.TimeEntries, nil
+}
+
+func (mock *MockTimeSource) GetProjectById(id int) (*api.Project, error) {
+	return &api.Project{Data: api.ProjectData{Id: id, Name: mock.ProjectName}}, nil
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/schedule"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+const defaultStatusDays = 7
+
+// Available day statuses
+const (
+	dayEmpty   = "empty"
+	daySynced  = "synced"
+	dayPending = "pending"
+	dayIssues  = "issues"
+)
+
+// NewStatusCmd creates a new Cobra Command that reports, per day, the time tracked and synced over the last days
+func NewStatusCmd(configManager config.Manager, sources map[string]source.TimeSource, syncLedger ledger.Ledger, clock schedule.Clock) *cobra.Command {
+	var days int
+	var sourceNames []string
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report the time tracked and synced over the last days",
+		Long: "Report, per day, the time tracked and synced over the last days (today included), " +
+			"along with pending entries, validation issues and entries of unmapped overhead projects.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+			sourceNames = configuredSources(sourceNames)
+			if err := validateConfig(sourceNames); err != nil {
+				return err
+			}
+			timeSource, err := selectSources(sources, sourceNames)
+			if err != nil {
+				return err
+			}
+			if days == 0 && config.IsSet(config.StatusDays) {
+				days = config.GetInt(config.StatusDays)
+			} else if days == 0 {
+				days = defaultStatusDays
+			}
+
+			location, err := userLocation(timeSource, timezoneFlag(cmd))
+			if err != nil {
+				return err
+			}
+
+			var statuses []dayStatus
+			projects := newProjectCache()
+			today := clock.Now().In(location)
+			for i := days - 1; i >= 0; i-- {
+				status, err := statusOf(timeSource, syncLedger, projects, location, today.AddDate(0, 0, -i).Format("2006-01-02"))
+				if err != nil {
+					return err
+				}
+				statuses = append(statuses, status)
+			}
+			return printStatus(cmd.OutOrStdout(), statuses)
+		},
+	}
+	cmd.Flags().IntVar(&days, "days", 0, fmt.Sprintf("number of days to report on (default %d)", defaultStatusDays))
+	cmd.Flags().StringSliceVar(&sourceNames, "source", nil, "time entry source(s) to report on: toggl, clockify, csv or ics (defaults to the ones in config, or toggl)")
+	return cmd
+}
+
+type dayStatus struct {
+	Date    string
+	Tracked int
+	Synced  int
+	Pending int
+	Issues  []string
+	Status  string
+}
+
+// statusOf compares the entries of the date with the worklogs recorded in the ledger for every configured sink
+func statusOf(timeSource source.TimeSource, syncLedger ledger.Ledger, projects *projectCache, location *time.Location, date string) (dayStatus, error) {
+	status := dayStatus{Date: date}
+	entries, err := getTimeEntriesForDate(timeSource, date, location)
+	if err != nil {
+		return status, err
+	}
+	entries, _, err = excludeEntries(timeSource, projects, entries, entryFilters{})
+	if err != nil {
+		return status, err
+	}
+	record, err := syncLedger.Get(date)
+	if err != nil {
+		return status, fmt.Errorf("error reading sync ledger: %s", err)
+	} else if record == nil {
+		record = &ledger.Record{Date: date}
+	}
+
+	for _, entry := range entries {
+		if ok, message := validateEntry(entry); !ok {
+			status.Issues = append(status.Issues, strings.TrimSpace(message))
+		}
+	}
+
+	strategy, err := summarizeStrategy("")
+	if err != nil {
+		return status, err
+	}
+
+	sinkNames := configuredSinks()
+	synced := make(map[string]int)
+	for _, entry := range summarize(stoppedEntries(entries), strategy, location) {
+		status.Tracked += entry.Duration
+		if ok, _ := validateEntry(entry); !ok {
+			status.Pending++
+			continue
+		}
+
+		ticket := entryTicket(entry)
+		if !isJiraTicket(entry) {
+			name, err := projectName(timeSource, projects, entry.Pid)
+			if err != nil {
+				return status, err
+			}
+			ticket = config.GetOverheadTicket(entry.Pid, name)
+			if ticket == "" {
+				status.Issues = append(status.Issues, fmt.Sprintf("Entry [%s] belongs to project [%s], which has no overhead ticket assigned.", entry.Description, name))
+				status.Pending++
+				continue
+			}
+		}
+
+		pending := false
+		for _, name := range sinkNames {
+			logged := 0
+			for _, worklog := range record.Synced(name, ticket, entry.Description, knownIDs(entry.SourceIds)) {
+				logged += worklog.Seconds
+			}
+			synced[name] += logged
+			if logged < entry.Duration {
+				pending = true
+			}
+		}
+		if pending {
+			status.Pending++
+		}
+	}
+
+	// Time only counts as synced once it was written to every sink
+	for i, name := range sinkNames {
+		if i == 0 || synced[name] < status.Synced {
+			status.Synced = synced[name]
+		}
+	}
+
+	sort.Strings(status.Issues)
+	switch {
+	case len(entries) == 0:
+		status.Status = dayEmpty
+	case len(status.Issues) != 0:
+		status.Status = dayIssues
+	case status.Pending != 0:
+		status.Status = dayPending
+	default:
+		status.Status = daySynced
+	}
+	return status, nil
+}
+
+func printStatus(out io.Writer, statuses []dayStatus) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "DATE\tTRACKED\tSYNCED\tPENDING\tSTATUS")
+	for _, status := range statuses {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\n", status.Date, formatHours(status.Tracked), formatHours(status.Synced), status.Pending, status.Status)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		for _, issue := range status.Issues {
+			if _, err := fmt.Fprintf(out, "%s: %s\n", status.Date, issue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// projectName returns the name of the project, caching it so every project is only retrieved once
+func projectName(timeSource source.TimeSource, projects *projectCache, pid int) (string, error) {
+	project, err := projects.project(timeSource, pid)
+	if err != nil {
+		return "", err
+	}
+	return project.Data.Name, nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCmd(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+	togglAPI := &DatedTimeSource{
+		TimeEntries: []api.TimeEntry{
+			// 2020-05-20: synced
+			{Id: 1, Start: time.Date(2020, 5, 20, 9, 0, 0, 0, time.UTC), Duration: 3600, Description: "ENG-1001"},
+			// 2020-05-21: partially synced
+			{Id: 2, Start: time.Date(2020, 5, 21, 9, 0, 0, 0, time.UTC), Duration: 3600, Description: "ENG-1001"},
+			{Id: 3, Start: time.Date(2020, 5, 21, 11, 0, 0, 0, time.UTC), Pid: 7, Duration: 1800, Description: "Team catch-up"},
+			// 2020-05-22: unmapped overhead project and a running timer
+			{Id: 4, Start: time.Date(2020, 5, 22, 9, 0, 0, 0, time.UTC), Pid: 8, Duration: 1800, Description: "Hiring"},
+			{Id: 5, Start: time.Date(2020, 5, 22, 11, 0, 0, 0, time.UTC), Duration: -1590145200, Description: "ENG-1002"},
+		},
+		Projects: map[int]string{7: "Meetings", 8: "Recruitment"},
+	}
+	syncLedger := &MockLedger{Records: []ledger.Record{
+		{Date: "2020-05-20", Complete: true, Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 3600}}},
+		{Date: "2020-05-21", Worklogs: []ledger.Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 3600}}},
+	}}
+	clock := &FakeClock{now: time.Date(2020, 5, 22, 18, 0, 0, 0, time.UTC)}
+	output := bytes.NewBufferString("")
+
+	cmd := NewStatusCmd(&MockConfigManager{InitOk: true}, map[string]source.TimeSource{source.Toggl: togglAPI}, syncLedger, clock)
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"--days", "4"})
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"DATE        TRACKED  SYNCED  PENDING  STATUS\n"+
+		"2020-05-19  0.00     0.00    0        empty\n"+
+		"2020-05-20  1.00     1.00    0        synced\n"+
+		"2020-05-21  1.50     1.00    1        pending\n"+
+		"2020-05-22  0.50     0.00    1        issues\n"+
+		"2020-05-22: Entry [ENG-1002] has a negative duration. If it's still in progress, you have to stop the task first.\n"+
+		"2020-05-22: Entry [Hiring] belongs to project [Recruitment], which has no overhead ticket assigned.\n",
+		output.String())
+}
+
+func TestStatusCmd_DaysFromConfig(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.StatusDays, 2)
+	output := bytes.NewBufferString("")
+
+	cmd := NewStatusCmd(&MockConfigManager{InitOk: true}, map[string]source.TimeSource{source.Toggl: &DatedTimeSource{}}, &MockLedger{}, &FakeClock{now: time.Date(2020, 5, 22, 18, 0, 0, 0, time.UTC)})
+	cmd.SetOut(output)
+	err := cmd.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, ""+
+		"DATE        TRACKED  SYNCED  PENDING  STATUS\n"+
+		"2020-05-21  0.00     0.00    0        empty\n"+
+		"2020-05-22  0.00     0.00    0        empty\n",
+		output.String())
+}
+
+// DatedTimeSource only returns the entries started within the period requested
+type DatedTimeSource struct {
+	TimeEntries []api.TimeEntry
+	Projects    map[int]string
+}
+
+func (mock *DatedTimeSource) GetTimeEntries(start time.Time, end time.Time) ([]api.TimeEntry, error) {
+	var entries []api.TimeEntry
+	for _, entry := range mock.TimeEntries {
+		if !entry.Start.Before(start) && entry.Start.Before(end) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (mock *DatedTimeSource) GetProjectById(id int) (*api.Project, error) {
+	return &api.Project{Data: api.ProjectData{Id: id, Name: mock.Projects[id]}}, nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+)
+
+// Ways of merging time entries into worklogs (see config.SyncSummarize)
+const (
+	// summarizeNone logs one worklog per time entry
+	summarizeNone = "none"
+	// summarizeDescription merges the entries of the same project with the same description
+	summarizeDescription = "description"
+	// summarizeTicket merges the entries logged on the same ticket (i.e. Jira ticket, or overhead project), combining their descriptions
+	summarizeTicket = "ticket"
+	// summarizeTicketPerDay is summarizeTicket, keeping the entries of different days apart
+	summarizeTicketPerDay = "ticket-per-day"
+)
+
+var summarizeStrategies = []string{summarizeNone, summarizeDescription, summarizeTicket, summarizeTicketPerDay}
+
+// descriptionSeparator separates the descriptions combined in a merged entry
+const descriptionSeparator = "; "
+
+var jiraTicketPrefix = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+// summarizeStrategy returns the summarization strategy requested (--summarize), or the one in config (by description by default)
+func summarizeStrategy(requested string) (string, error) {
+	strategy := requested
+	if strategy == "" {
+		strategy = configOrDefault(config.SyncSummarize, summarizeDescription)
+	}
+	if !contains(summarizeStrategies, strategy) {
+		return "", fmt.Errorf("unknown summarization strategy [%s]; available ones: none, description, ticket or ticket-per-day", strategy)
+	}
+	return strategy, nil
+}
+
+// summarize merges the entries following the strategy; days start at midnight in the location provided.
+// Merged entries keep the earliest start, the latest stop, every tag and the ids of all the entries merged (SourceIds),
+// and are returned in the order their first entry was provided.
+func summarize(entries []api.TimeEntry, strategy string, location *time.Location) []api.TimeEntry {
+	var summary []api.TimeEntry
+	positions := make(map[string]int)
+	for i, entry := range entries {
+		entry.SourceIds = []int{entry.Id}
+		key := summaryKey(entry, strategy, location)
+		if strategy == summarizeNone {
+			key = fmt.Sprintf("entry:%d", i)
+		}
+
+		if position, ok := positions[key]; ok {
+			summary[position] = merge(summary[position], entry)
+		} else {
+			positions[key] = len(summary)
+			entry.Tags = append([]string(nil), entry.Tags...)
+			summary = append(summary, entry)
+		}
+	}
+	return summary
+}
+
+func summaryKey(entry api.TimeEntry, strategy string, location *time.Location) string {
+	if strategy != summarizeTicket && strategy != summarizeTicketPerDay {
+		return fmt.Sprintf("%d:%s", entry.Pid, entry.Description)
+	}
+
+	key := "description:" + entry.Description
+	if isJiraTicket(entry) {
+		key = "ticket:" + entryTicket(entry)
+	} else if entry.Pid != 0 {
+		key = fmt.Sprintf("project:%d", entry.Pid)
+	}
+	if strategy == summarizeTicketPerDay {
+		key += "@" + entry.Start.In(location).Format("2006-01-02")
+	}
+	return key
+}
+
+func merge(merged api.TimeEntry, entry api.TimeEntry) api.TimeEntry {
+	merged.Duration += entry.Duration
+	merged.Start = earliest(merged.Start, entry.Start)
+	if entry.Stop.After(merged.Stop) {
+		merged.Stop = entry.Stop
+	}
+	for _, tag := range entry.Tags {
+		if !contains(merged.Tags, tag) {
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+	if !contains(strings.Split(merged.Description, descriptionSeparator), entry.Description) {
+		merged.Description += descriptionSeparator + entry.Description
+	}
+	merged.Billable = merged.Billable || entry.Billable
+	merged.Running = merged.Running || entry.Running
+	merged.SourceIds = append(merged.SourceIds, entry.SourceIds...)
+	return merged
+}
+
+func earliest(a time.Time, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// entryTicket returns the Jira ticket an entry is logged on: the ticket its description starts with (e.g. "ENG-1001 Code review"),
+// or the whole description otherwise
+func entryTicket(entry api.TimeEntry) string {
+	if ticket := jiraTicketPrefix.FindString(entry.Description); ticket != "" {
+		return ticket
+	}
+	return entry.Description
+}
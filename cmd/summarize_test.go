@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCmd_SummarizeByTicket(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncSummarize, summarizeTicket)
+	togglAPI := &MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Duration: 240, Description: "ENG-1001 Review"},
+			{Id: 2, Duration: 60, Description: "ENG-1002"},
+			{Id: 3, Duration: 120, Description: "ENG-1001 Fixes"},
+		},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001 Review; ENG-1001 Fixes", 360))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1002", 60))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestLogWork_NoSummaryWithRepeatedDescriptions(t *testing.T) {
+	setupBasicConfig()
+	entries := []api.TimeEntry{
+		{Id: 1, Duration: 240, Description: "ENG-1001"},
+		{Id: 2, Duration: 60, Description: "ENG-1001"},
+		{Duration: 30, Description: "ENG-1002"},
+		{Duration: 20, Description: "ENG-1002"},
+	}
+	jiraAPI := &MockJiraAPI{}
+	record := &ledger.Record{Date: "2020-05-22"}
+
+	failures := logWork(log.Default(), RejectAllInputController{t: t}, &MockTogglAPI{}, newProjectCache(), jiraSinks(jiraAPI), []string{sink.Jira}, record, time.UTC, summarize(entries, summarizeNone, time.UTC))
+	assert.Zero(t, failures)
+
+	// Entries with the same description are different worklogs, with or without ids
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 240))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 60))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1002", 30))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1002", 20))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+	assert.Len(t, record.Worklogs, 4)
+
+	// Syncing them again logs nothing
+	failures = logWork(log.Default(), RejectAllInputController{t: t}, &MockTogglAPI{}, newProjectCache(), jiraSinks(RejectAllCallsJiraAPI{t: t}), []string{sink.Jira}, record, time.UTC, summarize(entries[:2], summarizeNone, time.UTC))
+	assert.Zero(t, failures)
+}
+
+func TestLogWork_TicketSummaryWithEntriesAddedLater(t *testing.T) {
+	setupBasicConfig()
+	entries := []api.TimeEntry{
+		{Id: 1, Duration: 240, Description: "ENG-1001 Review"},
+		{Id: 2, Duration: 60, Description: "ENG-1001 Fixes"},
+	}
+	jiraAPI := &MockJiraAPI{}
+	record := &ledger.Record{Date: "2020-05-22"}
+
+	failures := logWork(log.Default(), RejectAllInputController{t: t}, &MockTogglAPI{}, newProjectCache(), jiraSinks(jiraAPI), []string{sink.Jira}, record, time.UTC, summarize(entries, summarizeTicket, time.UTC))
+	assert.Zero(t, failures)
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001 Review; ENG-1001 Fixes", 300))
+
+	// A new entry changes the description of the merged entry, but only its time is logged
+	entries = append(entries, api.TimeEntry{Id: 3, Duration: 120, Description: "ENG-1001 Tests"})
+	failures = logWork(log.Default(), RejectAllInputController{t: t}, &MockTogglAPI{}, newProjectCache(), jiraSinks(jiraAPI), []string{sink.Jira}, record, time.UTC, summarize(entries, summarizeTicket, time.UTC))
+	assert.Zero(t, failures)
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001 Review; ENG-1001 Fixes; ENG-1001 Tests", 120))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+	assert.Equal(t, []ledger.Worklog{
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001 Review; ENG-1001 Fixes", Seconds: 300, EntryIDs: []int{1, 2}},
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001 Review; ENG-1001 Fixes; ENG-1001 Tests", Seconds: 120, EntryIDs: []int{1, 2, 3}},
+	}, record.Worklogs)
+}
+
+func TestRootCmd_UnknownSummarizeStrategy(t *testing.T) {
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	cmd.SetArgs([]string{"--summarize", "weekly", "2020-05-22"})
+	err := cmd.Execute()
+	assert.EqualError(t, err, "unknown summarization strategy [weekly]; available ones: none, description, ticket or ticket-per-day")
+}
+
+func TestSummarize(t *testing.T) {
+	setupBasicConfig()
+	at := func(day int, hour int) time.Time {
+		return time.Date(2020, 5, day, hour, 0, 0, 0, time.UTC)
+	}
+	entries := []api.TimeEntry{
+		{Id: 1, Start: at(22, 10), Stop: at(22, 11), Duration: 3600, Description: "ENG-1001 Review", Tags: []string{"review"}},
+		{Id: 2, Pid: 7, Start: at(22, 9), Stop: at(22, 10), Duration: 3600, Description: "Team catch-up"},
+		{Id: 3, Start: at(23, 8), Stop: at(23, 9), Duration: 3600, Description: "ENG-1001 Fixes", Tags: []string{"review", "bug"}},
+		{Id: 4, Pid: 7, Start: at(22, 15), Stop: at(22, 16), Duration: 3600, Description: "Planning", Billable: true},
+		{Id: 5, Start: at(22, 12), Stop: at(22, 13), Duration: 3600, Description: "ENG-1001 Review"},
+	}
+
+	tests := []struct {
+		strategy string
+		expected []api.TimeEntry
+	}{
+		{summarizeNone, []api.TimeEntry{
+			{Id: 1, Start: at(22, 10), Stop: at(22, 11), Duration: 3600, Description: "ENG-1001 Review", Tags: []string{"review"}, SourceIds: []int{1}},
+			{Id: 2, Pid: 7, Start: at(22, 9), Stop: at(22, 10), Duration: 3600, Description: "Team catch-up", SourceIds: []int{2}},
+			{Id: 3, Start: at(23, 8), Stop: at(23, 9), Duration: 3600, Description: "ENG-1001 Fixes", Tags: []string{"review", "bug"}, SourceIds: []int{3}},
+			{Id: 4, Pid: 7, Start: at(22, 15), Stop: at(22, 16), Duration: 3600, Description: "Planning", Billable: true, SourceIds: []int{4}},
+			{Id: 5, Start: at(22, 12), Stop: at(22, 13), Duration: 3600, Description: "ENG-1001 Review", SourceIds: []int{5}},
+		}},
+		{summarizeDescription, []api.TimeEntry{
+			{Id: 1, Start: at(22, 10), Stop: at(22, 13), Duration: 7200, Description: "ENG-1001 Review", Tags: []string{"review"}, SourceIds: []int{1, 5}},
+			{Id: 2, Pid: 7, Start: at(22, 9), Stop: at(22, 10), Duration: 3600, Description: "Team catch-up", SourceIds: []int{2}},
+			{Id: 3, Start: at(23, 8), Stop: at(23, 9), Duration: 3600, Description: "ENG-1001 Fixes", Tags: []string{"review", "bug"}, SourceIds: []int{3}},
+			{Id: 4, Pid: 7, Start: at(22, 15), Stop: at(22, 16), Duration: 3600, Description: "Planning", Billable: true, SourceIds: []int{4}},
+		}},
+		{summarizeTicket, []api.TimeEntry{
+			{Id: 1, Start: at(22, 10), Stop: at(23, 9), Duration: 10800, Description: "ENG-1001 Review; ENG-1001 Fixes", Tags: []string{"review", "bug"}, SourceIds: []int{1, 3, 5}},
+			{Id: 2, Pid: 7, Start: at(22, 9), Stop: at(22, 16), Duration: 7200, Description: "Team catch-up; Planning", Billable: true, SourceIds: []int{2, 4}},
+		}},
+		{summarizeTicketPerDay, []api.TimeEntry{
+			{Id: 1, Start: at(22, 10), Stop: at(22, 13), Duration: 7200, Description: "ENG-1001 Review", Tags: []string{"review"}, SourceIds: []int{1, 5}},
+			{Id: 2, Pid: 7, Start: at(22, 9), Stop: at(22, 16), Duration: 7200, Description: "Team catch-up; Planning", Billable: true, SourceIds: []int{2, 4}},
+			{Id: 3, Start: at(23, 8), Stop: at(23, 9), Duration: 3600, Description: "ENG-1001 Fixes", Tags: []string{"review", "bug"}, SourceIds: []int{3}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.strategy, func(t *testing.T) {
+			assert.Equal(t, test.expected, summarize(entries, test.strategy, time.UTC))
+		})
+	}
+	assert.Equal(t, []string{"review"}, entries[0].Tags)
+}
+
+func TestEntryTicket(t *testing.T) {
+	assert.Equal(t, "ENG-1001", entryTicket(api.TimeEntry{Description: "ENG-1001"}))
+	assert.Equal(t, "ENG-1001", entryTicket(api.TimeEntry{Description: "ENG-1001 Review; ENG-1001 Fixes"}))
+	assert.Equal(t, "Team catch-up", entryTicket(api.TimeEntry{Description: "Team catch-up"}))
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+// timezoneFlag returns the value of the global --tz flag (empty if the command does not inherit it)
+func timezoneFlag(cmd *cobra.Command) string {
+	if flag := cmd.Flags().Lookup("tz"); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
+}
+
+// userLocation returns the time zone days start and end in: the one requested (--tz), the one in config,
+// the one in the profile of the user of the time source (e.g. Toggl), or the local time zone, in that order
+func userLocation(timeSource source.TimeSource, requested string) (*time.Location, error) {
+	if requested == "" && config.Get(config.SyncTimezone) == "" {
+		if provider, ok := timeSource.(source.UserDetailsProvider); ok {
+			me, err := provider.GetMe()
+			if err != nil {
+				return nil, fmt.Errorf("error fetching user details: %s", err)
+			}
+			return resolveLocation(log.Default(), requested, me)
+		}
+	}
+	return resolveLocation(log.Default(), requested, nil)
+}
+
+// currentDate returns the current date in the time zone of the user (see userLocation)
+func currentDate(timeSource source.TimeSource, requested string) (string, error) {
+	location, err := userLocation(timeSource, requested)
+	if err != nil {
+		return "", err
+	}
+	return time.Now().In(location).Format("2006-01-02"), nil
+}
+
+// resolveLocation is userLocation for the user details already fetched (nil if the time source provides none)
+func resolveLocation(logger *log.Logger, requested string, me *api.Me) (*time.Location, error) {
+	name, origin := requested, "--tz"
+	switch {
+	case name != "":
+	case config.Get(config.SyncTimezone) != "":
+		name, origin = config.Get(config.SyncTimezone), fmt.Sprintf("[%s]", config.SyncTimezone)
+	case me != nil && me.Data.Timezone != "":
+		name, origin = me.Data.Timezone, "user profile"
+	default:
+		return time.Local, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone [%s] (from %s): %s", name, origin, err)
+	}
+	logger.Printf("Using time zone %s (from %s)", location, origin)
+	return location, nil
+}
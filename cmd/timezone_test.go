@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCmd_DayInTogglTimezone(t *testing.T) {
+	togglAPI := &windowRecordingTogglAPI{MockTogglAPI: MockTogglAPI{
+		Me: api.Me{Data: api.PersonalInfo{Timezone: "Europe/Madrid"}},
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Start: time.Date(2020, 5, 21, 22, 30, 0, 0, time.UTC), Duration: 240, Description: "ENG-1001"},
+			{Id: 2, Start: time.Date(2020, 5, 22, 7, 0, 0, 0, time.UTC), Duration: 60, Description: "ENG-1001"},
+		},
+	}}
+	fileSink := &MockSink{}
+
+	setupBasicConfig()
+	config.Set(config.SyncSinks, []string{sink.CSV})
+	config.Set(config.CSVSinkPath, "/tmp/timesheet.csv")
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, map[string]source.TimeSource{source.Toggl: togglAPI}, map[string]sink.WorklogSink{sink.CSV: fileSink}, &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	// Madrid is 2 hours ahead of UTC in May
+	assert.Equal(t, "2020-05-21T22:00:00Z", togglAPI.Start.UTC().Format(time.RFC3339))
+	assert.Equal(t, "2020-05-22T22:00:00Z", togglAPI.End.UTC().Format(time.RFC3339))
+	assert.Len(t, fileSink.Worklogs, 1)
+	assert.Equal(t, "2020-05-22T00:30:00+02:00", fileSink.Worklogs[0].Started.Format(time.RFC3339))
+}
+
+func TestRootCmd_TimezoneFlag(t *testing.T) {
+	togglAPI := &windowRecordingTogglAPI{MockTogglAPI: MockTogglAPI{
+		Me:          api.Me{Data: api.PersonalInfo{Timezone: "Europe/Madrid"}},
+		TimeEntries: []api.TimeEntry{{Id: 1, Duration: 240, Description: "ENG-1001"}},
+	}}
+
+	setupBasicConfig()
+	config.Set(config.SyncTimezone, "America/New_York")
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, map[string]source.TimeSource{source.Toggl: togglAPI}, jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22", "--tz", "Asia/Tokyo"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "2020-05-21T15:00:00Z", togglAPI.Start.UTC().Format(time.RFC3339))
+}
+
+func TestRootCmd_CurrentDateInUserTimezone(t *testing.T) {
+	togglAPI := &windowRecordingTogglAPI{MockTogglAPI: MockTogglAPI{
+		Me: api.Me{Data: api.PersonalInfo{Timezone: "Pacific/Kiritimati"}},
+	}}
+
+	setupBasicConfig()
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, map[string]source.TimeSource{source.Toggl: togglAPI}, jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	cmd.SetArgs([]string{"--current-date"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	// Kiritimati is 14 hours ahead of UTC, so its current date is often not the one of the host
+	location, _ := time.LoadLocation("Pacific/Kiritimati")
+	assert.Equal(t, time.Now().In(location).Format("2006-01-02"), togglAPI.Start.In(location).Format("2006-01-02"))
+	assert.Equal(t, 0, togglAPI.Start.In(location).Hour())
+}
+
+func TestReportCmd_PeriodInUserTimezone(t *testing.T) {
+	togglAPI := &windowRecordingTogglAPI{MockTogglAPI: MockTogglAPI{
+		TimeEntries: []api.TimeEntry{
+			{Id: 1, Start: time.Date(2020, 5, 21, 22, 30, 0, 0, time.UTC), Duration: 240, Description: "ENG-1001"},
+			{Id: 2, Start: time.Date(2020, 5, 22, 7, 0, 0, 0, time.UTC), Duration: 60, Description: "ENG-1001"},
+		},
+	}}
+	output := bytes.NewBufferString("")
+
+	setupBasicConfig()
+	config.Set(config.SyncTimezone, "Europe/Madrid")
+
+	cmd := NewReportCmd(&MockConfigManager{InitOk: true}, map[string]source.TimeSource{source.Toggl: togglAPI})
+	cmd.SetOut(output)
+	cmd.SetArgs([]string{"--from", "2020-05-22", "--group-by", "day", "--format", "csv"})
+	err := cmd.Execute()
+	assert.Nil(t, err)
+
+	// Madrid is 2 hours ahead of UTC in May
+	assert.Equal(t, "2020-05-21T22:00:00Z", togglAPI.Start.UTC().Format(time.RFC3339))
+	assert.Equal(t, "2020-05-22T22:00:00Z", togglAPI.End.UTC().Format(time.RFC3339))
+	assert.Equal(t, ""+
+		"day,seconds,hours,percentage\n"+
+		"2020-05-22,300,0.08,100.0\n"+
+		"total,300,0.08,100.0\n", output.String())
+}
+
+func TestRootCmd_InvalidTimezone(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncTimezone, "Mars/Olympus_Mons")
+
+	cmd := NewRootCmd(&MockConfigManager{InitOk: true}, RejectAllInputController{t: t}, togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	cmd.SetArgs([]string{"2020-05-22"})
+	err := cmd.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestUserLocation(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{Me: api.Me{Data: api.PersonalInfo{Timezone: "Europe/Madrid"}}}
+
+	location, err := userLocation(togglAPI, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "Europe/Madrid", location.String())
+
+	config.Set(config.SyncTimezone, "America/New_York")
+	location, err = userLocation(togglAPI, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "America/New_York", location.String())
+
+	location, err = userLocation(togglAPI, "Asia/Tokyo")
+	assert.Nil(t, err)
+	assert.Equal(t, "Asia/Tokyo", location.String())
+
+	// Sources without user details fall back to the local time zone
+	config.Unset(config.SyncTimezone)
+	location, err = userLocation(&windowRecordingSource{}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Local, location)
+}
+
+// windowRecordingTogglAPI records the time period requested
+type windowRecordingTogglAPI struct {
+	MockTogglAPI
+	Start time.Time
+	End   time.Time
+}
+
+func (mock *windowRecordingTogglAPI) GetTimeEntries(start time.Time, end time.Time) ([]api.TimeEntry, error) {
+	mock.Start, mock.End = start, end
+	return mock.MockTogglAPI.GetTimeEntries(start, end)
+}
+
+// windowRecordingSource is a time source providing no user details
+type windowRecordingSource struct{}
+
+func (*windowRecordingSource) GetTimeEntries(time.Time, time.Time) ([]api.TimeEntry, error) {
+	return nil, nil
+}
+
+func (*windowRecordingSource) GetProjectById(int) (*api.Project, error) {
+	return nil, nil
+}
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os/signal"
+	"sort"
+	"strings"
+	stdsync "sync"
+	"syscall"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
+	"github.com/spf13/cobra"
+)
+
+const defaultUIListenAddress = "127.0.0.1:8090"
+
+// uiTokenHeader is the header the page sends the session token in (see uiHandler)
+const uiTokenHeader = "X-Toggl-Sync-Token"
+
+//go:embed ui.html
+var uiPage []byte
+
+// NewUICmd creates a new Cobra Command that serves a local web page to review and trigger syncs
+func NewUICmd(configManager config.Manager, sources map[string]source.TimeSource, sinks map[string]sink.WorklogSink, syncLedger ledger.Ledger) *cobra.Command {
+	var listenAddress string
+	cmd := &cobra.Command{
+		Use:   "ui",
+		Short: "Serve a local web page to review and trigger syncs",
+		Long: "Serve a local web page to review the time entries of a date, assign overhead tickets and trigger (or dry-run) the sync. " +
+			"The page is backed by JSON endpoints under /api.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readConfig(configManager, profileFlag(cmd)); err != nil {
+				return err
+			}
+			if err := validateConfig(configuredSources(nil)); err != nil {
+				return err
+			}
+
+			handler, err := newUIHandler(configManager, listenAddress, timezoneFlag(cmd), sources, sinks, syncLedger)
+			if err != nil {
+				return err
+			}
+			server := &http.Server{Addr: listenAddress, Handler: handler}
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				_ = server.Shutdown(context.Background())
+			}()
+
+			log.Printf("Serving toggl-sync UI on http://%s", listenAddress)
+			if err := server.ListenAndServe(); err != http.ErrServerClosed {
+				return err
+			}
+			log.Print("Server stopped")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&listenAddress, "listen", defaultUIListenAddress, "address to listen on")
+	return cmd
+}
+
+type uiSummary struct {
+	Date    string    `json:"date"`
+	Synced  bool      `json:"synced"`
+	Entries []uiEntry `json:"entries"`
+}
+
+type uiEntry struct {
+	Description string `json:"description"`
+	Seconds     int    `json:"seconds"`
+	ProjectID   int    `json:"projectId,omitempty"`
+	Project     string `json:"project,omitempty"`
+	Ticket      string `json:"ticket,omitempty"`
+	Overhead    bool   `json:"overhead"`
+	Issue       string `json:"issue,omitempty"`
+}
+
+type uiOverheadRequest struct {
+	ProjectID int    `json:"projectId,omitempty"`
+	Project   string `json:"project"`
+	Ticket    string `json:"ticket"`
+}
+
+type uiSyncRequest struct {
+	Date   string `json:"date"`
+	DryRun bool   `json:"dryRun"`
+	Force  bool   `json:"force"`
+}
+
+type uiSyncResult struct {
+	OK    bool     `json:"ok"`
+	Error string   `json:"error,omitempty"`
+	Log   []string `json:"log"`
+}
+
+// uiHandler serves the UI page and its JSON endpoints.
+// Requests are handled one at a time, as they share config and the ledger.
+// Other sites the browser visits must not be able to use the endpoints: requests are only answered for the address listened on
+// (or localhost and IP addresses, which DNS rebinding cannot forge), and API calls must send back the session token embedded in the page.
+type uiHandler struct {
+	configManager config.Manager
+	sources       map[string]source.TimeSource
+	sinks         map[string]sink.WorklogSink
+	syncLedger    ledger.Ledger
+	listenAddress string
+	timezone      string
+	token         string
+	page          []byte
+	mux           *http.ServeMux
+	mutex         stdsync.Mutex
+}
+
+func newUIHandler(configManager config.Manager, listenAddress string, timezone string, sources map[string]source.TimeSource, sinks map[string]sink.WorklogSink, syncLedger ledger.Ledger) (*uiHandler, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("error generating session token: %s", err)
+	}
+	handler := &uiHandler{
+		configManager: configManager,
+		sources:       sources,
+		sinks:         sinks,
+		syncLedger:    syncLedger,
+		listenAddress: listenAddress,
+		timezone:      timezone,
+		token:         hex.EncodeToString(token),
+	}
+	handler.page = bytes.Replace(uiPage, []byte("{{token}}"), []byte(handler.token), 1)
+
+	handler.mux = http.NewServeMux()
+	handler.mux.HandleFunc("/", handler.servePage)
+	handler.mux.HandleFunc("/api/summary", handler.serveSummary)
+	handler.mux.HandleFunc("/api/overhead", handler.serveOverhead)
+	handler.mux.HandleFunc("/api/sync", handler.serveSync)
+	return handler, nil
+}
+
+func (handler *uiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !handler.allowedHost(r.Host) {
+		http.Error(w, fmt.Sprintf("host [%s] not allowed", r.Host), http.StatusForbidden)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if parsed, err := url.Parse(origin); err != nil || parsed.Scheme != "http" || !handler.allowedHost(parsed.Host) {
+			http.Error(w, fmt.Sprintf("origin [%s] not allowed", origin), http.StatusForbidden)
+			return
+		}
+	}
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(uiTokenHeader)), []byte(handler.token)) != 1 {
+			http.Error(w, "missing or invalid session token (reload the page)", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet {
+			if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+				http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+		}
+	}
+	handler.mux.ServeHTTP(w, r)
+}
+
+// allowedHost tells whether the host (of the request, or of its origin) is the address listened on, localhost or an IP address,
+// on the same port
+func (handler *uiHandler) allowedHost(host string) bool {
+	listenHost, listenPort, err := net.SplitHostPort(handler.listenAddress)
+	if err != nil {
+		return false
+	}
+	name, port, err := net.SplitHostPort(host)
+	if err != nil || port != listenPort {
+		return false
+	}
+	return strings.EqualFold(name, listenHost) || strings.EqualFold(name, "localhost") || net.ParseIP(name) != nil
+}
+
+func (handler *uiHandler) servePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(handler.page)
+}
+
+func (handler *uiHandler) serveSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	timeSource, err := selectSources(handler.sources, configuredSources(nil))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	syncDate, err := uiDate(timeSource, handler.timezone, r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := buildUISummary(timeSource, handler.syncLedger, syncDate, handler.timezone)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, summary)
+}
+
+func (handler *uiHandler) serveOverhead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	var req uiOverheadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("error unmarshalling request: %s", err), http.StatusBadRequest)
+		return
+	}
+	req.Project, req.Ticket = strings.TrimSpace(req.Project), strings.TrimSpace(req.Ticket)
+	if req.Project == "" || req.Ticket == "" {
+		http.Error(w, "project and ticket are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Saving configuration: entries for project [%s] will be tracked as [%s] from now on", req.Project, req.Ticket)
+	config.SetOverheadMapping(config.OverheadMapping{ProjectID: req.ProjectID, Project: req.Project, Ticket: req.Ticket})
+	if err := handler.configManager.Persist(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, req)
+}
+
+func (handler *uiHandler) serveSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	var req uiSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("error unmarshalling request: %s", err), http.StatusBadRequest)
+		return
+	}
+	timeSource, err := selectSources(handler.sources, configuredSources(nil))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	syncDate, err := uiDate(timeSource, handler.timezone, req.Date)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The progress of the sync is logged as usual, and captured for the page too
+	var output bytes.Buffer
+	logger := log.New(io.MultiWriter(log.Writer(), &output), log.Prefix(), log.Flags())
+	err = sync(uiInputController{}, timeSource, handler.sinks, handler.syncLedger, syncDate, syncOptions{dryRun: req.DryRun, force: req.Force, timezone: handler.timezone, logger: logger})
+
+	result := uiSyncResult{OK: err == nil, Log: strings.Split(strings.TrimSpace(output.String()), "\n")}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	writeJSON(w, result)
+}
+
+// buildUISummary summarizes the entries of the date (in the time zone requested, see userLocation), like printSummary,
+// resolving the ticket each one would be logged on
+func buildUISummary(timeSource source.TimeSource, syncLedger ledger.Ledger, syncDate string, timezone string) (*uiSummary, error) {
+	location, err := userLocation(timeSource, timezone)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := getTimeEntriesForDate(timeSource, syncDate, location)
+	if err != nil {
+		return nil, err
+	}
+	entries, _, err = excludeEntries(timeSource, newProjectCache(), entries, entryFilters{})
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := summarizeStrategy("")
+	if err != nil {
+		return nil, err
+	}
+	record, err := syncLedger.Get(syncDate)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sync ledger: %s", err)
+	}
+
+	summary := &uiSummary{Date: syncDate, Synced: record != nil && record.Complete, Entries: []uiEntry{}}
+	for _, entry := range summarize(entries, strategy, location) {
+		uiEntry := uiEntry{Description: entry.Description, Seconds: entry.Duration}
+		if ok, message := validateEntry(entry); !ok {
+			uiEntry.Issue = strings.TrimSpace(message)
+		} else if isJiraTicket(entry) {
+			uiEntry.Ticket = entryTicket(entry)
+		} else {
+			project, err := timeSource.GetProjectById(entry.Pid)
+			if err != nil {
+				return nil, fmt.Errorf("retrieving project information failed with an error: %s", err)
+			}
+			uiEntry.Project = project.Data.Name
+			uiEntry.Overhead = true
+			uiEntry.ProjectID = project.Data.Id
+			uiEntry.Ticket = config.GetOverheadTicket(project.Data.Id, project.Data.Name)
+			if uiEntry.Ticket == "" {
+				uiEntry.Issue = "No overhead ticket assigned to the project"
+			}
+		}
+		summary.Entries = append(summary.Entries, uiEntry)
+	}
+	sort.Slice(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].Description < summary.Entries[j].Description
+	})
+	return summary, nil
+}
+
+// uiDate validates the date requested, defaulting to the current one (see currentDate)
+func uiDate(timeSource source.TimeSource, timezone string, date string) (string, error) {
+	if date == "" {
+		return currentDate(timeSource, timezone)
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return "", fmt.Errorf("invalid date [%s]; expected format is YYYY-MM-DD", date)
+	}
+	return date, nil
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Writing response failed with an error: %s", err)
+	}
+}
+
+// uiInputController rejects all input requests, as overhead tickets are assigned through the page
+type uiInputController struct{}
+
+func (uiInputController) requestTextInput(string) (string, error) {
+	return "", fmt.Errorf("no overhead ticket assigned (assign one on the page first)")
+}
+
+func (uiInputController) requestPassword(string) (string, error) {
+	return "", fmt.Errorf("no user input available from the page")
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+var uiTestEntries = []api.TimeEntry{
+	{Id: 1, Duration: 120, Description: "ENG-1001"},
+	{Id: 2, Duration: 60, Description: "ENG-1001"},
+	{Id: 3, Pid: 7, Duration: 1800, Description: "Team catch-up"},
+}
+
+// uiRequest creates a request the way the page sends it: to the address listened on, with the session token
+func uiRequest(handler *uiHandler, method string, target string, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Host = handler.listenAddress
+	req.Header.Set(uiTokenHeader, handler.token)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+func TestUI_Page(t *testing.T) {
+	setupBasicConfig()
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodGet, "/", ""))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "<title>toggl-sync</title>")
+	assert.Contains(t, recorder.Body.String(), handler.token)
+}
+
+func TestUI_Summary(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{
+		TimeEntries: uiTestEntries,
+		Project:     api.Project{Data: api.ProjectData{Id: 7, Name: "Meetings"}},
+	}
+	syncLedger := &MockLedger{Records: []ledger.Record{{Date: "2020-05-22", Complete: true}}}
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(togglAPI), jiraSinks(&RejectAllCallsJiraAPI{t: t}), syncLedger)
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodGet, "/api/summary?date=2020-05-22", ""))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var summary uiSummary
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &summary))
+	assert.Equal(t, uiSummary{
+		Date:   "2020-05-22",
+		Synced: true,
+		Entries: []uiEntry{
+			{Description: "ENG-1001", Seconds: 180, Ticket: "ENG-1001"},
+			{Description: "Team catch-up", Seconds: 1800, ProjectID: 7, Project: "Meetings", Overhead: true, Issue: "No overhead ticket assigned to the project"},
+		},
+	}, summary)
+}
+
+func TestUI_Summary_InvalidDate(t *testing.T) {
+	setupBasicConfig()
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodGet, "/api/summary?date=22/05/2020", ""))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestUI_AssignOverheadTicket(t *testing.T) {
+	setupBasicConfig()
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodPut, "/api/overhead", `{"projectId": 7, "project": "Meetings", "ticket": " MGMT-1 "}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []config.OverheadMapping{{ProjectID: 7, Project: "Meetings", Ticket: "MGMT-1"}}, config.GetOverheadMappings())
+}
+
+func TestUI_DryRun(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{TimeEntries: uiTestEntries[:2]}
+	syncLedger := &MockLedger{}
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(togglAPI), jiraSinks(&RejectAllCallsJiraAPI{t: t}), syncLedger)
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodPost, "/api/sync", `{"date": "2020-05-22", "dryRun": true}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var result uiSyncResult
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	assert.True(t, result.OK)
+	assert.NotEmpty(t, result.Log)
+	assert.Empty(t, syncLedger.Records)
+}
+
+func TestUI_Sync(t *testing.T) {
+	setupBasicConfig()
+	config.SetOverheadMapping(config.OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+	togglAPI := &MockTogglAPI{
+		TimeEntries: uiTestEntries,
+		Project:     api.Project{Data: api.ProjectData{Id: 7, Name: "Meetings"}},
+	}
+	jiraAPI := &MockJiraAPI{}
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(togglAPI), jiraSinks(jiraAPI), &MockLedger{})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodPost, "/api/sync", `{"date": "2020-05-22"}`))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("ENG-1001", 180))
+	assert.Nil(t, jiraAPI.VerifyWorkLogged("Team catch-up", 1800))
+	assert.Nil(t, jiraAPI.VerifyNoOtherWorkLogged())
+}
+
+func TestUI_Timezone(t *testing.T) {
+	setupBasicConfig()
+	config.Set(config.SyncTimezone, "Europe/Madrid")
+	togglAPI := &windowRecordingTogglAPI{MockTogglAPI: MockTogglAPI{TimeEntries: []api.TimeEntry{{Id: 1, Duration: 240, Description: "ENG-1001"}}}}
+	kiritimati, _ := time.LoadLocation("Pacific/Kiritimati")
+	dayStart := time.Date(2020, 5, 22, 0, 0, 0, 0, kiritimati)
+
+	// --tz takes precedence over the time zone in config, for summaries and syncs alike
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "Pacific/Kiritimati", togglSources(togglAPI), jiraSinks(&MockJiraAPI{}), &MockLedger{})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodGet, "/api/summary?date=2020-05-22", ""))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, dayStart.Equal(togglAPI.Start), "summary starts at %s", togglAPI.Start)
+
+	togglAPI.Start = time.Time{}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodPost, "/api/sync", `{"date": "2020-05-22", "dryRun": true}`))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, dayStart.Equal(togglAPI.Start), "sync starts at %s", togglAPI.Start)
+}
+
+func TestUI_SyncFailsOnValidationErrors(t *testing.T) {
+	setupBasicConfig()
+	togglAPI := &MockTogglAPI{TimeEntries: []api.TimeEntry{{Id: 1, Duration: 60}}}
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(togglAPI), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, uiRequest(handler, http.MethodPost, "/api/sync", `{"date": "2020-05-22"}`))
+
+	var result uiSyncResult
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	assert.False(t, result.OK)
+	assert.Equal(t, "validation failed", result.Error)
+}
+
+func TestUI_RejectsRequestsFromOtherSites(t *testing.T) {
+	setupBasicConfig()
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name     string
+		request  func() *http.Request
+		expected int
+	}{
+		{"host of another site (DNS rebinding)", func() *http.Request {
+			req := uiRequest(handler, http.MethodGet, "/", "")
+			req.Host = "attacker.example.com:8090"
+			return req
+		}, http.StatusForbidden},
+		{"origin of another site", func() *http.Request {
+			req := uiRequest(handler, http.MethodPost, "/api/sync", `{"date": "2020-05-22"}`)
+			req.Header.Set("Origin", "http://attacker.example.com")
+			return req
+		}, http.StatusForbidden},
+		{"missing session token", func() *http.Request {
+			req := uiRequest(handler, http.MethodGet, "/api/summary?date=2020-05-22", "")
+			req.Header.Del(uiTokenHeader)
+			return req
+		}, http.StatusForbidden},
+		{"form submission", func() *http.Request {
+			req := uiRequest(handler, http.MethodPost, "/api/sync", `{"date": "2020-05-22"}`)
+			req.Header.Set("Content-Type", "text/plain")
+			return req
+		}, http.StatusUnsupportedMediaType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, test.request())
+			assert.Equal(t, test.expected, recorder.Code)
+		})
+	}
+}
+
+func TestUI_AllowsLocalhost(t *testing.T) {
+	setupBasicConfig()
+
+	handler, err := newUIHandler(&MockConfigManager{}, defaultUIListenAddress, "", togglSources(&MockTogglAPI{}), jiraSinks(&RejectAllCallsJiraAPI{t: t}), &MockLedger{})
+	assert.Nil(t, err)
+	req := uiRequest(handler, http.MethodGet, "/", "")
+	req.Host = "localhost:8090"
+	req.Header.Set("Origin", "http://localhost:8090")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
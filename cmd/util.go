@@ -0,0 +1,11 @@
+package cmd
+
+// contains returns whether the value is one of the values provided
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
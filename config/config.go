@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -21,51 +22,446 @@ const (
 	JiraUsername   string = "jira.username"
 	JiraPassword   string = "jira.password"
 	JiraProjectKey string = "jira.project.key"
+	SyncSinks      string = "sync.sinks"
+	CSVSinkPath    string = "sink.csv.path"
+	JSONSinkPath   string = "sink.json.path"
+	TempoServerURL string = "tempo.server.url"
+	TempoToken     string = "tempo.token"
+	TempoAccountID string = "tempo.author.account.id"
+
+	SyncSources         string = "sync.sources"
+	ClockifyServerURL   string = "clockify.server.url"
+	ClockifyAPIKey      string = "clockify.api.key"
+	ClockifyWorkspaceID string = "clockify.workspace.id"
+	CSVSourcePath       string = "source.csv.path"
+	ICSSourcePath       string = "source.ics.path"
+	ICSSourceProject    string = "source.ics.project"
+
+	LedgerPath    string = "sync.ledger.path"
+	SyncTimezone  string = "sync.timezone"
+	SyncRunning   string = "sync.running.entries"
+	SyncSummarize string = "sync.summarize"
+
+	SyncExcludeTags        string = "sync.exclude.tags"
+	SyncExcludeProjects    string = "sync.exclude.projects"
+	SyncExcludeDescription string = "sync.exclude.description"
+	SyncExcludeBillable    string = "sync.exclude.billable"
+
+	SyncCommentProject       string = "sync.comment.project"
+	SyncCommentOverhead      string = "sync.comment.overhead"
+	SyncCommentFooter        string = "sync.comment.footer.text"
+	SyncCommentFooterEnabled string = "sync.comment.footer.enabled"
+
+	DaemonSchedule    string = "daemon.schedule"
+	DaemonCatchUpDays string = "daemon.catch.up.days"
+
+	StatusDays string = "status.days"
+
+	TogglWebhookSecret   string = "toggl.webhook.secret"
+	WebhookListenAddress string = "webhook.listen.address"
+
+	TeamConfigURL      string = "team.config.url"
+	TeamConfigCacheTTL string = "team.config.cache.ttl"
+	TeamConfigCacheDir string = "team.config.cache.dir"
+)
+
+// Available Tempo mapping settings (see TempoMappingKey)
+const (
+	TempoMappingAccount    string = "account"
+	TempoMappingBillable   string = "billable"
+	TempoMappingAttributes string = "attributes"
 )
 
 // Get returns the current value of the key in the config map (if any exists)
 func Get(key string) string {
-	return viper.GetString(key)
+	if values := teamValues(key); values != nil {
+		return values.GetString(key)
+	}
+	return viper.GetString(profileKey(key))
 }
 
 // GetSlice returns the current values associated with the key in the config map (if any exist)
 func GetSlice(key string) []string {
-	return viper.GetStringSlice(key)
+	if values := teamValues(key); values != nil {
+		return values.GetStringSlice(key)
+	}
+	return viper.GetStringSlice(profileKey(key))
+}
+
+// GetBool returns the current value of the key in the config map as a boolean (false if it does not exist)
+func GetBool(key string) bool {
+	if values := teamValues(key); values != nil {
+		return values.GetBool(key)
+	}
+	return viper.GetBool(profileKey(key))
+}
+
+// GetInt returns the current value of the key in the config map as an integer (0 if it does not exist)
+func GetInt(key string) int {
+	if values := teamValues(key); values != nil {
+		return values.GetInt(key)
+	}
+	return viper.GetInt(profileKey(key))
+}
+
+// IsSet returns true if the key has a value in the config map
+func IsSet(key string) bool {
+	return viper.IsSet(profileKey(key)) || teamValues(key) != nil
 }
 
 // Set overrides the value of the key in the config map
 func Set(key string, value interface{}) {
-	viper.Set(key, value)
+	apply(func() { viper.Set(profileKey(key), value) })
+}
+
+// Unset removes the key (and any key nested under it) from the config map.
+// It returns false if the key had no value (values of the team config file cannot be removed).
+func Unset(key string) (removed bool) {
+	apply(func() { removed = unset(key) })
+	return removed
 }
 
-const jiraOverheadKeyPrefix = "jira.overhead"
+func unset(key string) bool {
+	if !viper.IsSet(profileKey(key)) {
+		return false
+	}
+
+	// viper cannot remove keys, so the config map is rebuilt without it
+	settings := viper.AllSettings()
+	path := strings.Split(strings.ToLower(profileKey(key)), ".")
+	keep := 0
+	if activeProfile != "" {
+		keep = 2 // profiles.<name> is kept, even if empty
+	}
+	removeNested(settings, path, keep)
+	file := viper.ConfigFileUsed()
+	viper.Reset()
+	viper.SetConfigFile(file)
+	_ = viper.MergeConfigMap(settings)
+	return true
+}
+
+// removeNested removes the last element of the path, along with any parent left empty (except for the first "keep" ones)
+func removeNested(settings map[string]interface{}, path []string, keep int) {
+	if len(path) == 1 {
+		delete(settings, path[0])
+		return
+	}
+	if nested, ok := settings[path[0]].(map[string]interface{}); ok {
+		removeNested(nested, path[1:], keep-1)
+		if len(nested) == 0 && keep <= 0 {
+			delete(settings, path[0])
+		}
+	}
+}
 
-// GetAllOverheadKeys returns all overhead keys from config, if any exist
-func GetAllOverheadKeys() []string {
-	overheadKeys := make([]string, 0)
+// Keys returns all keys with a value in the config map (or the team config file), sorted.
+// Keys of other profiles (and the default profile selection) are left out.
+func Keys() []string {
+	keys := make([]string, 0)
+	for _, key := range teamConfigKeys {
+		if teamValues(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	prefix := profileKey("")
 	for _, key := range viper.AllKeys() {
-		if keyName := strings.TrimPrefix(key, jiraOverheadKeyPrefix+"."); !strings.EqualFold(key, keyName) {
-			overheadKeys = append(overheadKeys, keyName)
+		if activeProfile != "" && strings.HasPrefix(key, prefix) {
+			keys = append(keys, strings.TrimPrefix(key, prefix))
+		} else if activeProfile == "" && key != defaultProfileKey && !strings.HasPrefix(key, profilesPrefix+".") {
+			keys = append(keys, key)
 		}
 	}
-	return overheadKeys
+	sort.Strings(keys)
+	return keys
+}
+
+// IsSecret returns true if the key holds a credential (e.g. passwords or API tokens)
+func IsSecret(key string) bool {
+	for _, suffix := range []string{"password", "token", "secret", "api.key"} {
+		if strings.HasSuffix(strings.ToLower(key), suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	profilesPrefix    = "profiles"
+	defaultProfileKey = "profile"
+)
+
+var activeProfile string
+
+// UseProfile makes all subsequent reads and writes target the named profile.
+// An empty name targets the top-level configuration.
+func UseProfile(name string) {
+	activeProfile = strings.ToLower(name)
+}
+
+// ActiveProfile returns the name of the profile in use (empty when using the top-level configuration)
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// GetProfiles returns the names of all profiles from config, if any exist
+func GetProfiles() []string {
+	profiles := make([]string, 0)
+	for name := range viper.GetStringMap(profilesPrefix) {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// HasProfile returns true if the named profile exists in config
+func HasProfile(name string) bool {
+	return viper.IsSet(fmt.Sprintf("%s.%s", profilesPrefix, strings.ToLower(name)))
 }
 
-// GetOverheadKey returns the specified overhead key from config, if any exists
-func GetOverheadKey(key string) string {
-	return viper.GetString(generateOverheadKeyFrom(key))
+// GetDefaultProfile returns the profile to be used when none is explicitly requested (if any exists)
+func GetDefaultProfile() string {
+	return viper.GetString(defaultProfileKey)
 }
 
-// SetOverheadKey accepts a new value for the specified overhead key to be stored in config
-func SetOverheadKey(key string, value string) {
-	viper.Set(generateOverheadKeyFrom(key), value)
+// SetDefaultProfile overrides the profile to be used when none is explicitly requested
+func SetDefaultProfile(name string) {
+	apply(func() { viper.Set(defaultProfileKey, strings.ToLower(name)) })
 }
 
-func generateOverheadKeyFrom(key string) string {
-	return fmt.Sprintf("%s.%s", jiraOverheadKeyPrefix, key)
+// globalKeys apply to the configuration file as a whole, so they are never stored per profile
+var globalKeys = []string{Version, Backups}
+
+func profileKey(key string) string {
+	if activeProfile == "" {
+		return key
+	}
+	for _, global := range globalKeys {
+		if key == global {
+			return key
+		}
+	}
+	return fmt.Sprintf("%s.%s.%s", profilesPrefix, activeProfile, key)
+}
+
+// OverheadMappings is the config key holding the overhead mappings (see OverheadMapping)
+const OverheadMappings = "jira.overheads"
+
+// legacyOverheadKeyPrefix holds the overhead tickets of previous versions, as "jira.overhead.<project name>: <ticket>".
+// Viper keys are case-insensitive and split on dots, so project names were not always preserved.
+const legacyOverheadKeyPrefix = "jira.overhead"
+
+// OverheadMapping links a project of the time entry source to the Jira ticket its entries are logged on
+type OverheadMapping struct {
+	ProjectID int    `mapstructure:"projectId"`
+	Project   string `mapstructure:"project"`
+	Ticket    string `mapstructure:"ticket"`
+	// Origin is OriginTeam for mappings supplied by the team config file, and empty otherwise
+	Origin string `mapstructure:"-"`
+}
+
+// GetOverheadMappings returns all overhead mappings from config, if any exist,
+// followed by those of the team config file that are not overridden
+func GetOverheadMappings() []OverheadMapping {
+	mappings := personalOverheadMappings()
+	for _, mapping := range teamOverheadMappings() {
+		if indexOfOverheadMapping(mappings, mapping.ProjectID, mapping.Project) == -1 {
+			mappings = append(mappings, mapping)
+		}
+	}
+	return mappings
+}
+
+func personalOverheadMappings() []OverheadMapping {
+	mappings := make([]OverheadMapping, 0)
+	_ = viper.UnmarshalKey(profileKey(OverheadMappings), &mappings)
+	return mappings
+}
+
+// FindOverheadMapping returns the overhead mapping of the project, looking it up by project ID first, and by name afterwards.
+// Projects with no ID (i.e. zero) are only looked up by name, and mappings with no ID match any project of the same name.
+func FindOverheadMapping(projectID int, project string) (OverheadMapping, bool) {
+	mappings := GetOverheadMappings()
+	if i := indexOfOverheadMapping(mappings, projectID, project); i != -1 {
+		return mappings[i], true
+	}
+	return OverheadMapping{}, false
+}
+
+// GetOverheadTicket returns the ticket of the project (see FindOverheadMapping), or an empty string if it has no mapping
+func GetOverheadTicket(projectID int, project string) string {
+	mapping, _ := FindOverheadMapping(projectID, project)
+	return mapping.Ticket
+}
+
+// SetOverheadMapping stores the overhead mapping in config, replacing any existing mapping of the same project
+func SetOverheadMapping(mapping OverheadMapping) {
+	mapping.Origin = ""
+	apply(func() {
+		mappings := personalOverheadMappings()
+		if i := indexOfOverheadMapping(mappings, mapping.ProjectID, mapping.Project); i != -1 {
+			if mapping.ProjectID == 0 {
+				mapping.ProjectID = mappings[i].ProjectID
+			}
+			mappings[i] = mapping
+		} else {
+			mappings = append(mappings, mapping)
+		}
+		setOverheadMappings(mappings)
+	})
+}
+
+// RemoveOverheadMapping removes the overhead mapping of the project (see FindOverheadMapping), returning false if it did not exist.
+// Mappings of the team config file cannot be removed.
+func RemoveOverheadMapping(projectID int, project string) (removed bool) {
+	apply(func() {
+		mappings := personalOverheadMappings()
+		if i := indexOfOverheadMapping(mappings, projectID, project); i != -1 {
+			setOverheadMappings(append(mappings[:i], mappings[i+1:]...))
+			removed = true
+		}
+	})
+	return removed
+}
+
+func indexOfOverheadMapping(mappings []OverheadMapping, projectID int, project string) int {
+	if projectID != 0 {
+		for i, mapping := range mappings {
+			if mapping.ProjectID == projectID {
+				return i
+			}
+		}
+	}
+	// Mappings of other projects (with the same name) are left out
+	for i, mapping := range mappings {
+		if strings.EqualFold(mapping.Project, project) && (projectID == 0 || mapping.ProjectID == 0) {
+			return i
+		}
+	}
+	return -1
+}
+
+func setOverheadMappings(mappings []OverheadMapping) {
+	// Mappings are stored as plain maps, matching the way they are read from disk
+	values := make([]map[string]interface{}, 0, len(mappings))
+	for _, mapping := range mappings {
+		value := map[string]interface{}{"project": mapping.Project, "ticket": mapping.Ticket}
+		if mapping.ProjectID != 0 {
+			value["projectId"] = mapping.ProjectID
+		}
+		values = append(values, value)
+	}
+	Set(OverheadMappings, values)
+}
+
+const jiraInstancesPrefix = "jira.instances"
+
+// GetJiraInstances returns the names of all additional Jira instances from config, if any exist
+func GetJiraInstances() []string {
+	instances := make([]string, 0)
+	for name := range viper.GetStringMap(profileKey(jiraInstancesPrefix)) {
+		instances = append(instances, name)
+	}
+	sort.Strings(instances)
+	return instances
+}
+
+// JiraInstanceKey returns the config key holding a Jira setting (e.g. JiraServerURL) for the specified Jira instance
+func JiraInstanceKey(instance string, key string) string {
+	return fmt.Sprintf("%s.%s.%s", jiraInstancesPrefix, instance, strings.TrimPrefix(key, "jira."))
+}
+
+// GetJiraInstanceForTicket returns the name of the Jira instance owning the ticket, based on its project key.
+// An empty name is returned when the ticket belongs to the default Jira instance.
+// Project keys are case-sensitive, like when telling tickets apart from other time entries.
+func GetJiraInstanceForTicket(ticket string) string {
+	projectKey := strings.SplitN(ticket, "-", 2)[0]
+	for _, instance := range GetJiraInstances() {
+		for _, key := range GetSlice(JiraInstanceKey(instance, JiraProjectKey)) {
+			if key == projectKey {
+				return instance
+			}
+		}
+	}
+	return ""
+}
+
+// GetAllJiraProjectKeys returns the project keys of the default Jira instance, followed by those of any additional instance
+func GetAllJiraProjectKeys() []string {
+	projectKeys := append([]string{}, GetSlice(JiraProjectKey)...)
+	for _, instance := range GetJiraInstances() {
+		projectKeys = append(projectKeys, GetSlice(JiraInstanceKey(instance, JiraProjectKey))...)
+	}
+	return projectKeys
+}
+
+const tempoMappingsPrefix = "tempo.mappings"
+
+// TempoMappingKey returns the config key holding a Tempo mapping setting (e.g. TempoMappingAccount)
+// for the specified Jira project key or overhead project name
+func TempoMappingKey(name string, setting string) string {
+	return fmt.Sprintf("%s.%s.%s", tempoMappingsPrefix, strings.ToLower(name), setting)
+}
+
+// HasTempoMapping returns true if a Tempo mapping exists for the specified Jira project key or overhead project name
+func HasTempoMapping(name string) bool {
+	return IsSet(fmt.Sprintf("%s.%s", tempoMappingsPrefix, strings.ToLower(name)))
+}
+
+// WorklogVisibilities is the config key holding the worklog visibility rules (see VisibilityRule)
+const WorklogVisibilities = "jira.visibility"
+
+// VisibilityRule restricts the worklogs of an overhead project (identified by ID or, otherwise, by name) or of a Jira ticket
+// to the members of a Jira group or project role
+type VisibilityRule struct {
+	ProjectID int    `mapstructure:"projectId"`
+	Project   string `mapstructure:"project"`
+	Ticket    string `mapstructure:"ticket"`
+	// Type is "group" or "role", and Value the name of the group or role
+	Type  string `mapstructure:"type"`
+	Value string `mapstructure:"value"`
+}
+
+// GetVisibilityRules returns all worklog visibility rules from config, if any exist, followed by those of the team config file
+func GetVisibilityRules() []VisibilityRule {
+	rules := make([]VisibilityRule, 0)
+	_ = viper.UnmarshalKey(profileKey(WorklogVisibilities), &rules)
+	if team != nil {
+		var teamRules []VisibilityRule
+		_ = team.UnmarshalKey(WorklogVisibilities, &teamRules)
+		rules = append(rules, teamRules...)
+	}
+	return rules
+}
+
+// FindVisibilityRule returns the worklog visibility rule of the overhead project (looked up like FindOverheadMapping does)
+// or, if it has none, the one of the ticket. Work that is not overhead is looked up by ticket alone (i.e. no project).
+func FindVisibilityRule(projectID int, project string, ticket string) (VisibilityRule, bool) {
+	rules := GetVisibilityRules()
+	if projectID != 0 {
+		for _, rule := range rules {
+			if rule.ProjectID == projectID {
+				return rule, true
+			}
+		}
+	}
+	for _, rule := range rules {
+		if project != "" && strings.EqualFold(rule.Project, project) && (projectID == 0 || rule.ProjectID == 0) {
+			return rule, true
+		}
+	}
+	for _, rule := range rules {
+		if rule.ProjectID == 0 && rule.Project == "" && strings.EqualFold(rule.Ticket, ticket) {
+			return rule, true
+		}
+	}
+	return VisibilityRule{}, false
 }
 
 // Reset clears all configuration loaded from disk (contents on disk are not removed)
 func Reset() {
+	activeProfile = ""
+	team = nil
+	changes, removals = nil, nil
 	viper.Reset()
 }
@@ -1,11 +1,11 @@
 package config
 
 import (
-	"os"
-
 	"github.com/spf13/viper"
 )
 
+const defaultConfigFile = "/usr/local/etc/toggl-sync.yaml"
+
 // Manager isolates side effects from reading and persisting config values
 type Manager interface {
 	Init() (ok bool, err error)
@@ -19,11 +19,13 @@ type ViperConfigManager struct{}
 // If the file exists and is readable, Init returns ok=true, err=nil (after loading the configuration)
 // If the file does not exist, Init returns ok=false, err=nil
 // If the file is found, but cannot be read, Init returns ok=false and the error back to the client
+// If the file has an outdated layout, Init upgrades it in memory (see Migrate); the file is only upgraded by Persist
 func (mgr *ViperConfigManager) Init() (ok bool, err error) {
 	viper.SetConfigName("toggl-sync")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("/usr/local/etc")
 
+	changes, removals = nil, nil
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			return false, nil
@@ -31,25 +33,17 @@ func (mgr *ViperConfigManager) Init() (ok bool, err error) {
 		return false, err
 	}
 
-	return true, nil
+	return true, upgrade()
 }
 
-// Persist saves the current config to disk
+// Persist saves the changes made to the config to disk, stamped with the current layout version.
+// Changes saved by other processes since Init are kept (see writeConfigFile).
 func (mgr *ViperConfigManager) Persist() error {
-	// Creating file beforehand as viper.WriteConfig fails otherwise
-	err := mgr.createConfigFile()
-	if err != nil {
-		return err
-	}
+	Set(Version, CurrentVersion)
 
-	return viper.WriteConfig()
-}
-
-func (mgr *ViperConfigManager) createConfigFile() error {
-	f, err := os.OpenFile("/usr/local/etc/toggl-sync.yaml", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		return err
+	path := FileUsed()
+	if path == "" {
+		path = defaultConfigFile
 	}
-
-	return f.Close()
+	return writeConfigFile(path)
 }
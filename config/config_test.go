@@ -24,33 +24,72 @@ func TestSet(t *testing.T) {
 	assertSame(t, viper.GetString("toggl.username"), "togglUser2")
 }
 
-func TestGetOverheadKey(t *testing.T) {
-	viper.Set("jira.overhead.meetings", "someValue")
-	assertSame(t, GetOverheadKey("meetings"), "someValue")
+func TestGetOverheadMappings(t *testing.T) {
+	Reset()
+	viper.Set(OverheadMappings, []interface{}{
+		map[string]interface{}{"projectId": 7, "project": "Ops.Support", "ticket": "OPS-1"},
+		map[string]interface{}{"project": "R&D", "ticket": "ENG-2"},
+	})
+
+	mappings := GetOverheadMappings()
+	if !reflect.DeepEqual(mappings, []OverheadMapping{{ProjectID: 7, Project: "Ops.Support", Ticket: "OPS-1"}, {Project: "R&D", Ticket: "ENG-2"}}) {
+		t.Errorf("Unexpected overhead mappings: %v", mappings)
+	}
 }
 
-func TestSetOverheadKey(t *testing.T) {
-	SetOverheadKey("meetings", "someValue2")
-	assertSame(t, viper.GetString("jira.overhead.meetings"), "someValue2")
+func TestFindOverheadMapping(t *testing.T) {
+	Reset()
+	SetOverheadMapping(OverheadMapping{ProjectID: 7, Project: "Meetings", Ticket: "MGMT-1"})
+	SetOverheadMapping(OverheadMapping{Project: "R&D", Ticket: "ENG-2"})
+
+	assertSame(t, GetOverheadTicket(7, "Renamed meetings"), "MGMT-1")
+	assertSame(t, GetOverheadTicket(0, "meetings"), "MGMT-1")
+	assertSame(t, GetOverheadTicket(8, "Meetings"), "")
+	assertSame(t, GetOverheadTicket(9, "r&d"), "ENG-2")
+	_, ok := FindOverheadMapping(9, "Hiring")
+	assertSame(t, ok, false)
 }
 
-func TestGetAllOverheadKeys(t *testing.T) {
-	viper.Set("something", "value")
-	viper.Set("jira.overhead.meetings", "overhead1")
-	viper.Set("somethingElse", "otherValue")
-	viper.Set("jira.overhead.cooking", "overhead2")
+func TestFindVisibilityRule(t *testing.T) {
+	Reset()
+	viper.Set(WorklogVisibilities, []interface{}{
+		map[string]interface{}{"projectId": 7, "project": "Ops.Interviews", "type": "group", "value": "hr"},
+		map[string]interface{}{"project": "1:1s", "type": "role", "value": "Managers"},
+		map[string]interface{}{"ticket": "MGMT-42", "type": "role", "value": "Leads"},
+	})
 
-	overheadKeys := GetAllOverheadKeys()
-	if len(overheadKeys) != 2 {
-		t.Errorf("There should be only %d overhead keys, but there were %d", 2, len(overheadKeys))
-	}
-	for _, k := range overheadKeys {
-		if k != "meetings" && k != "cooking" {
-			t.Errorf("Expecting %s as overhead keys but got %s", [2]string{"meetings", "cooking"}, overheadKeys)
-		}
+	rule, _ := FindVisibilityRule(7, "Renamed interviews", "MGMT-1")
+	assertSame(t, rule.Value, "hr")
+	rule, _ = FindVisibilityRule(0, "ops.interviews", "MGMT-1")
+	assertSame(t, rule.Value, "hr")
+	rule, _ = FindVisibilityRule(9, "1:1s", "MGMT-42")
+	assertSame(t, rule.Value, "Managers")
+	rule, _ = FindVisibilityRule(8, "Ops.Interviews", "MGMT-42")
+	assertSame(t, rule.Value, "Leads")
+	_, ok := FindVisibilityRule(0, "", "MGMT-1")
+	assertSame(t, ok, false)
+}
+
+func TestSetOverheadMapping(t *testing.T) {
+	Reset()
+	SetOverheadMapping(OverheadMapping{ProjectID: 7, Project: "Meetings", Ticket: "MGMT-1"})
+	SetOverheadMapping(OverheadMapping{Project: "meetings", Ticket: "MGMT-2"})
+	SetOverheadMapping(OverheadMapping{ProjectID: 8, Project: "Hiring", Ticket: "MGMT-3"})
+
+	mappings := GetOverheadMappings()
+	if !reflect.DeepEqual(mappings, []OverheadMapping{{ProjectID: 7, Project: "meetings", Ticket: "MGMT-2"}, {ProjectID: 8, Project: "Hiring", Ticket: "MGMT-3"}}) {
+		t.Errorf("Unexpected overhead mappings: %v", mappings)
 	}
 }
 
+func TestRemoveOverheadMapping(t *testing.T) {
+	Reset()
+	SetOverheadMapping(OverheadMapping{ProjectID: 7, Project: "Meetings", Ticket: "MGMT-1"})
+	assertSame(t, RemoveOverheadMapping(7, ""), true)
+	assertSame(t, RemoveOverheadMapping(7, ""), false)
+	assertSame(t, len(GetOverheadMappings()), 0)
+}
+
 func TestReset(t *testing.T) {
 	viper.Set("something", "value")
 	Reset()
@@ -75,3 +114,147 @@ func assertSameSlice(t *testing.T, first []string, second []string) {
 		t.Errorf("Expected [%s] to equal [%s] but it did not", first, second)
 	}
 }
+
+func TestGetJiraInstances(t *testing.T) {
+	Reset()
+	viper.Set("jira.instances.ops.server.url", "http://ops")
+	viper.Set("jira.instances.cloud.server.url", "http://cloud")
+	assertSameSlice(t, GetJiraInstances(), []string{"cloud", "ops"})
+}
+
+func TestJiraInstanceKey(t *testing.T) {
+	assertSame(t, JiraInstanceKey("ops", JiraServerURL), "jira.instances.ops.server.url")
+	assertSame(t, JiraInstanceKey("ops", JiraProjectKey), "jira.instances.ops.project.key")
+}
+
+func TestGetJiraInstanceForTicket(t *testing.T) {
+	Reset()
+	viper.Set(JiraProjectKey, []string{"ENG"})
+	viper.Set("jira.instances.ops.project.key", []string{"OPS", "SUP"})
+	assertSame(t, GetJiraInstanceForTicket("SUP-12"), "ops")
+	assertSame(t, GetJiraInstanceForTicket("ENG-12"), "")
+	assertSame(t, GetJiraInstanceForTicket("MGMT-1"), "")
+	assertSame(t, GetJiraInstanceForTicket("sup-12"), "")
+}
+
+func TestGetAllJiraProjectKeys(t *testing.T) {
+	Reset()
+	viper.Set(JiraProjectKey, []string{"ENG"})
+	viper.Set("jira.instances.ops.project.key", []string{"OPS"})
+	assertSameSlice(t, GetAllJiraProjectKeys(), []string{"ENG", "OPS"})
+}
+
+func TestUseProfile(t *testing.T) {
+	Reset()
+	viper.Set("toggl.username", "topLevelUser")
+	viper.Set("profiles.clienta.toggl.username", "clientUser")
+
+	UseProfile("ClientA")
+	assertSame(t, ActiveProfile(), "clienta")
+	assertSame(t, Get(TogglUsername), "clientUser")
+
+	Set(JiraProjectKey, []string{"CLA"})
+	assertSameSlice(t, viper.GetStringSlice("profiles.clienta.jira.project.key"), []string{"CLA"})
+
+	UseProfile("")
+	assertSame(t, Get(TogglUsername), "topLevelUser")
+}
+
+func TestUseProfile_OverheadMappings(t *testing.T) {
+	Reset()
+	SetOverheadMapping(OverheadMapping{Project: "meetings", Ticket: "overhead1"})
+	UseProfile("clienta")
+	SetOverheadMapping(OverheadMapping{Project: "cooking", Ticket: "overhead2"})
+
+	assertSame(t, len(GetOverheadMappings()), 1)
+	assertSame(t, GetOverheadTicket(0, "cooking"), "overhead2")
+	assertSame(t, GetOverheadTicket(0, "meetings"), "")
+	Reset()
+}
+
+func TestGetProfiles(t *testing.T) {
+	Reset()
+	viper.Set("profiles.clientb.toggl.username", "userB")
+	viper.Set("profiles.clienta.toggl.username", "userA")
+	assertSameSlice(t, GetProfiles(), []string{"clienta", "clientb"})
+	assertSame(t, HasProfile("ClientA"), true)
+	assertSame(t, HasProfile("clientc"), false)
+}
+
+func TestDefaultProfile(t *testing.T) {
+	Reset()
+	UseProfile("clienta")
+	SetDefaultProfile("ClientB")
+	assertSame(t, GetDefaultProfile(), "clientb")
+	assertSame(t, viper.GetString("profile"), "clientb")
+	Reset()
+}
+
+func TestGetBool(t *testing.T) {
+	viper.Set("tempo.mappings.eng.billable", true)
+	assertSame(t, GetBool(TempoMappingKey("ENG", TempoMappingBillable)), true)
+	assertSame(t, GetBool("tempo.mappings.unknown.billable"), false)
+}
+
+func TestIsSet(t *testing.T) {
+	viper.Set("something", "value")
+	assertSame(t, IsSet("something"), true)
+	assertSame(t, IsSet("somethingMissing"), false)
+}
+
+func TestTempoMappingKey(t *testing.T) {
+	assertSame(t, TempoMappingKey("Meetings", TempoMappingAccount), "tempo.mappings.meetings.account")
+}
+
+func TestHasTempoMapping(t *testing.T) {
+	viper.Set("tempo.mappings.eng.account", "ACC-1")
+	assertSame(t, HasTempoMapping("ENG"), true)
+	assertSame(t, HasTempoMapping("OPS"), false)
+}
+
+func TestGetInt(t *testing.T) {
+	viper.Set(DaemonCatchUpDays, 7)
+	assertSame(t, GetInt(DaemonCatchUpDays), 7)
+}
+
+func TestUnset(t *testing.T) {
+	Reset()
+	viper.SetConfigFile("test-config.yml")
+	viper.Set("toggl.username", "topLevelUser")
+	viper.Set("jira.overhead.meetings", "overhead1")
+	viper.Set("profiles.clienta.toggl.username", "clientUser")
+
+	UseProfile("clienta")
+	assertSame(t, Unset(TogglUsername), true)
+	assertSame(t, Unset(TogglUsername), false)
+	assertSame(t, IsSet("toggl"), false)
+	assertSame(t, HasProfile("clienta"), true)
+
+	UseProfile("")
+	assertSame(t, Unset("jira.overhead.meetings"), true)
+	assertSame(t, IsSet("jira"), false)
+	assertSame(t, Get(TogglUsername), "topLevelUser")
+	assertSame(t, FileUsed(), "test-config.yml")
+	Reset()
+}
+
+func TestKeys(t *testing.T) {
+	Reset()
+	viper.Set("toggl.username", "topLevelUser")
+	viper.Set("jira.server.url", "http://jira")
+	viper.Set("profile", "clienta")
+	viper.Set("profiles.clienta.toggl.username", "clientUser")
+
+	assertSameSlice(t, Keys(), []string{"jira.server.url", "toggl.username"})
+	UseProfile("clienta")
+	assertSameSlice(t, Keys(), []string{"toggl.username"})
+	Reset()
+}
+
+func TestIsSecret(t *testing.T) {
+	assertSame(t, IsSecret(JiraPassword), true)
+	assertSame(t, IsSecret(ClockifyAPIKey), true)
+	assertSame(t, IsSecret(TempoToken), true)
+	assertSame(t, IsSecret(JiraInstanceKey("ops", JiraPassword)), true)
+	assertSame(t, IsSecret(JiraUsername), false)
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the config key holding the version of the configuration layout (files without one are version 0)
+const Version = "config.version"
+
+// CurrentVersion is the version of the configuration layout used by this release (i.e. the number of migrations)
+const CurrentVersion = 2
+
+// migration upgrades the configuration layout to the next version, returning a description of every change made
+type migration struct {
+	description string
+	apply       func() []string
+}
+
+// migrations lists every change of the configuration layout, in order: migrations[i] upgrades version i to i+1.
+// New migrations must be appended, never reordered nor removed.
+var migrations = []migration{
+	{description: "Toggl moved its API to a new host", apply: migrateTogglServerURL},
+	{description: "Overhead tickets are stored as records keyed by project ID", apply: migrateOverheadKeys},
+}
+
+// legacyTogglServerURLs are the Toggl API URLs replaced by "https://api.track.toggl.com/api/v8"
+var legacyTogglServerURLs = []string{"https://www.toggl.com/api/v8", "https://toggl.com/api/v8"}
+
+// Migrate upgrades the configuration layout loaded in memory to CurrentVersion, one version at a time.
+// It returns a description of every change made (nothing is saved to disk).
+func Migrate() ([]string, error) {
+	version := viper.GetInt(Version)
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("configuration file has version %d, but this release only supports up to version %d! Please, upgrade toggl-sync", version, CurrentVersion)
+	}
+
+	var changes []string
+	for ; version < CurrentVersion; version++ {
+		for _, change := range migrations[version].apply() {
+			changes = append(changes, fmt.Sprintf("v%d -> v%d (%s): %s", version, version+1, migrations[version].description, change))
+		}
+		next := version + 1
+		apply(func() { viper.Set(Version, next) })
+	}
+	return changes, nil
+}
+
+// upgrade migrates the configuration loaded from disk (if outdated) in memory only, so read-only commands never rewrite it.
+// The file itself is upgraded the next time the configuration is saved, after saving a copy of the previous version next to it
+// (see writeConfigFile).
+func upgrade() error {
+	version := viper.GetInt(Version)
+	if version == CurrentVersion {
+		return nil
+	}
+
+	changes, err := Migrate()
+	if err != nil {
+		return err
+	}
+	log.Printf("Configuration file has version %d; using it as version %d (the file is upgraded the next time the configuration is saved)", version, CurrentVersion)
+	for _, change := range changes {
+		log.Printf("Configuration upgrade %s", change)
+	}
+	return nil
+}
+
+// backupVersion saves a copy of the configuration file (as <path>.v<version>.bak) before it is upgraded from an outdated version
+func backupVersion(path string, version int) error {
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := copyFile(path, backup); err != nil {
+		return err
+	}
+	log.Printf("Upgrading configuration file from version %d to %d (previous file saved as %s)", version, CurrentVersion, backup)
+	return nil
+}
+
+func copyFile(from string, to string) error {
+	source, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(target, source); err != nil {
+		_ = target.Close()
+		return err
+	}
+	return target.Close()
+}
+
+// forEachProfile runs the function for the top-level configuration and every profile, restoring the active profile afterwards
+func forEachProfile(apply func(profile string)) {
+	profile := activeProfile
+	for _, name := range append([]string{""}, GetProfiles()...) {
+		activeProfile = name
+		apply(name)
+	}
+	activeProfile = profile
+}
+
+func describeProfile(profile string) string {
+	if profile == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (profile [%s])", profile)
+}
+
+func migrateTogglServerURL() []string {
+	var changes []string
+	forEachProfile(func(profile string) {
+		url := Get(TogglServerURL)
+		for _, legacyURL := range legacyTogglServerURLs {
+			if strings.EqualFold(strings.TrimSuffix(url, "/"), legacyURL) {
+				Set(TogglServerURL, "https://api.track.toggl.com/api/v8")
+				changes = append(changes, fmt.Sprintf("[%s] changed from %s to https://api.track.toggl.com/api/v8%s", TogglServerURL, url, describeProfile(profile)))
+			}
+		}
+	})
+	return changes
+}
+
+func migrateOverheadKeys() []string {
+	var changes []string
+	forEachProfile(func(profile string) {
+		names := legacyOverheadNames(profile)
+		prefix := profileKey(legacyOverheadKeyPrefix) + "."
+		for _, key := range viper.AllKeys() {
+			if strings.HasPrefix(key, prefix) {
+				project, ticket := strings.TrimPrefix(key, prefix), viper.GetString(key)
+				name, ok := names[project]
+				if ok {
+					project = name
+				}
+				change := fmt.Sprintf("[%s.%s] moved to [%s]%s", legacyOverheadKeyPrefix, project, OverheadMappings, describeProfile(profile))
+				if !ok {
+					change += fmt.Sprintf("; project name read as [%s], check it matches the Toggl project ('overhead list')", project)
+				}
+				SetOverheadMapping(OverheadMapping{Project: project, Ticket: ticket})
+				changes = append(changes, change)
+			}
+		}
+		if IsSet(legacyOverheadKeyPrefix) {
+			removeOnWrite(legacyOverheadKeyPrefix)
+		}
+	})
+	return changes
+}
+
+// legacyOverheadNames reads the project names of the legacy overhead tickets of the profile straight from the configuration file,
+// as viper lowercases them (and splits them on dots). Names are keyed by the way viper reads them.
+func legacyOverheadNames(profile string) map[string]string {
+	names := make(map[string]string)
+	content, err := os.ReadFile(FileUsed())
+	if err != nil {
+		return names
+	}
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(content, &settings); err != nil {
+		return names
+	}
+
+	path := strings.Split(legacyOverheadKeyPrefix, ".")
+	if profile != "" {
+		path = append([]string{profilesPrefix, profile}, path...)
+	}
+	for _, key := range path {
+		settings = nestedSettings(settings, key)
+	}
+	collectNames(settings, "", names)
+	return names
+}
+
+// nestedSettings returns the settings nested under the key (matched case-insensitively, like viper does)
+func nestedSettings(settings map[string]interface{}, key string) map[string]interface{} {
+	for name, value := range settings {
+		if nested, ok := value.(map[string]interface{}); ok && strings.EqualFold(name, key) {
+			return nested
+		}
+	}
+	return nil
+}
+
+func collectNames(settings map[string]interface{}, parent string, names map[string]string) {
+	for name, value := range settings {
+		if parent != "" {
+			name = parent + "." + name
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			collectNames(nested, name, names)
+		} else {
+			names[strings.ToLower(name)] = name
+		}
+	}
+}
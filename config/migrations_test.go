@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCurrentVersion(t *testing.T) {
+	assertSame(t, CurrentVersion, len(migrations))
+}
+
+func TestMigrate_TogglServerURL(t *testing.T) {
+	Reset()
+	viper.Set(TogglServerURL, "https://www.toggl.com/api/v8/")
+	viper.Set("profiles.clienta.toggl.server.url", "https://api.track.toggl.com/api/v8")
+
+	changes, err := Migrate()
+	assertSame(t, err, nil)
+	assertSame(t, len(changes), 1)
+	assertSame(t, Get(TogglServerURL), "https://api.track.toggl.com/api/v8")
+	assertSame(t, viper.GetInt(Version), CurrentVersion)
+	Reset()
+}
+
+func TestMigrate_OverheadKeys(t *testing.T) {
+	Reset()
+	viper.Set("jira.overhead.meetings", "MGMT-1")
+	viper.Set("profiles.clienta.jira.overhead.cooking", "MGMT-2")
+	viper.Set("profiles.clienta.toggl.username", "clientUser")
+	UseProfile("clienta")
+
+	changes, err := Migrate()
+	assertSame(t, err, nil)
+	assertSameSlice(t, changes, []string{
+		"v1 -> v2 (Overhead tickets are stored as records keyed by project ID): [jira.overhead.meetings] moved to [jira.overheads]; " +
+			"project name read as [meetings], check it matches the Toggl project ('overhead list')",
+		"v1 -> v2 (Overhead tickets are stored as records keyed by project ID): [jira.overhead.cooking] moved to [jira.overheads] (profile [clienta]); " +
+			"project name read as [cooking], check it matches the Toggl project ('overhead list')",
+	})
+	assertSame(t, ActiveProfile(), "clienta")
+	assertSame(t, GetOverheadTicket(0, "Cooking"), "MGMT-2")
+	UseProfile("")
+	assertSame(t, GetOverheadTicket(0, "Meetings"), "MGMT-1")
+	// The legacy keys are removed from the file when it is next written
+	assertSame(t, len(removals), 2)
+
+	// Up-to-date layouts are left untouched
+	changes, err = Migrate()
+	assertSame(t, err, nil)
+	assertSame(t, len(changes), 0)
+	Reset()
+}
+
+func TestMigrate_OverheadKeysKeepProjectNames(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	writeFile(t, path, "jira:\n  overhead:\n    Ops.Support: OPS-1\n    R&D: ENG-2\nprofiles:\n  clienta:\n    jira:\n      overhead:\n        Team Meetings: MGMT-1\n")
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := Migrate()
+	assertSame(t, err, nil)
+	assertSame(t, len(changes), 3)
+	assertSame(t, strings.Contains(strings.Join(changes, "\n"), "project name read as"), false)
+	mappings := GetOverheadMappings()
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Project < mappings[j].Project })
+	if !reflect.DeepEqual(mappings, []OverheadMapping{{Project: "Ops.Support", Ticket: "OPS-1"}, {Project: "R&D", Ticket: "ENG-2"}}) {
+		t.Errorf("Unexpected overhead mappings: %v", mappings)
+	}
+	UseProfile("clienta")
+	assertSame(t, GetOverheadTicket(0, "Team Meetings"), "MGMT-1")
+	assertSame(t, GetOverheadMappings()[0].Project, "Team Meetings")
+	Reset()
+}
+
+func TestMigrate_NewerVersion(t *testing.T) {
+	Reset()
+	viper.Set(Version, CurrentVersion+1)
+
+	_, err := Migrate()
+	if err == nil {
+		t.Errorf("Migrating a configuration newer than supported should fail")
+	}
+	Reset()
+}
+
+func TestUpgrade(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	original := "jira:\n  overhead:\n    meetings: MGMT-1\n"
+	writeFile(t, path, original)
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The configuration is upgraded in memory only
+	err := upgrade()
+	assertSame(t, err, nil)
+	assertSame(t, len(GetOverheadMappings()), 1)
+	unchanged, _ := os.ReadFile(path)
+	assertSame(t, string(unchanged), original)
+	_, err = os.Stat(path + ".v0.bak")
+	assertSame(t, os.IsNotExist(err), true)
+
+	// The file is upgraded (and backed up) once the configuration is saved
+	err = (&ViperConfigManager{}).Persist()
+	assertSame(t, err, nil)
+	backup, _ := os.ReadFile(path + ".v0.bak")
+	assertSame(t, string(backup), original)
+	upgraded, _ := os.ReadFile(path)
+	assertSame(t, strings.Contains(string(upgraded), "version: 2"), true)
+	assertSame(t, strings.Contains(string(upgraded), "ticket: MGMT-1"), true)
+	assertSame(t, strings.Contains(string(upgraded), "meetings: MGMT-1"), false)
+
+	// Once upgraded, files are not backed up again
+	_ = os.Remove(path + ".v0.bak")
+	err = upgrade()
+	assertSame(t, err, nil)
+	err = (&ViperConfigManager{}).Persist()
+	assertSame(t, err, nil)
+	_, err = os.Stat(path + ".v2.bak")
+	assertSame(t, os.IsNotExist(err), true)
+	Reset()
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Backups is the config key holding the number of previous versions of the configuration file to keep
+const Backups = "config.backups"
+
+const defaultBackups = 3
+
+// change is a change made to the config map (e.g. by Set), along with the profile that was active when it was made
+type change struct {
+	profile string
+	apply   func()
+}
+
+var (
+	// changes lists the changes made to the config map since it was last read or written, so they can be applied again
+	// on top of the file on disk right before writing it (see writeConfigFile)
+	changes []change
+	// removals lists the keys to remove from the file when it is next written (see removeOnWrite)
+	removals []change
+	// applying is true while a change is being applied, so changes made by other changes (e.g. Set by SetOverheadMapping)
+	// are not listed twice
+	applying bool
+	// lockTimeout is how long writeConfigFile waits for another process to release the lock
+	lockTimeout = 10 * time.Second
+	// staleLockAge is the age after which a lock is considered abandoned (e.g. by a process that crashed)
+	staleLockAge = 2 * time.Minute
+)
+
+// apply makes a change to the config map, listing it so it is kept if another process saves the file in the meantime
+func apply(fn func()) {
+	if applying {
+		fn()
+		return
+	}
+	applying = true
+	defer func() { applying = false }()
+	fn()
+	changes = append(changes, change{profile: activeProfile, apply: fn})
+}
+
+// removeOnWrite removes the key (e.g. a key of a previous layout) from the file when it is next written, rather than from
+// the config map right away: Unset rebuilds the config map (see unset), which must not happen while it is being loaded (see Init)
+func removeOnWrite(key string) {
+	removals = append(removals, change{profile: activeProfile, apply: func() { unset(key) }})
+}
+
+// writeConfigFile saves the current config to the file atomically, so a failure never leaves it empty or half-written:
+// the config is written to a temporary file first, synced to disk and renamed over the previous file.
+// The previous versions of the file are kept as <path>.1 (newest) to <path>.N (see Backups).
+// A lock file (<path>.lock) is held while the file is read again and the changes made since are applied on top of it,
+// so concurrent runs never overwrite each other's changes.
+// Files written with a newer layout version are also copied as <path>.v<version>.bak first (see upgrade).
+func writeConfigFile(path string) error {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return fmt.Errorf("error locking configuration file: %s", err)
+	}
+	defer unlock()
+
+	version, err := reload(path)
+	if err != nil {
+		return fmt.Errorf("error reading configuration file: %s", err)
+	}
+	if version < viper.GetInt(Version) {
+		if err := backupVersion(path, version); err != nil {
+			return fmt.Errorf("error backing up configuration file: %s", err)
+		}
+	}
+
+	// The temporary file keeps the extension, so viper knows the format to use; it is created with owner-only permissions
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".toggl-sync-*"+filepath.Ext(path))
+	if err != nil {
+		return fmt.Errorf("error creating temporary configuration file: %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	_ = tmp.Close()
+
+	if err := viper.WriteConfigAs(tmp.Name()); err != nil {
+		return fmt.Errorf("error writing configuration: %s", err)
+	}
+	if err := syncFile(tmp.Name()); err != nil {
+		return fmt.Errorf("error writing configuration: %s", err)
+	}
+
+	if err := rotateBackups(path); err != nil {
+		return fmt.Errorf("error backing up configuration file: %s", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error replacing configuration file: %s", err)
+	}
+	// Syncing the directory makes the rename itself durable; not every platform supports it, so errors are ignored
+	_ = syncFile(filepath.Dir(path))
+	changes, removals = nil, nil
+	return nil
+}
+
+// reload reads the file again (if it exists), and applies the changes made since it was last read on top of it.
+// It returns the layout version of the file on disk (CurrentVersion if there is no file, so it is not backed up).
+func reload(path string) (version int, err error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return CurrentVersion, nil
+	}
+
+	file := viper.ConfigFileUsed()
+	viper.Reset()
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		return 0, err
+	}
+	version = viper.GetInt(Version)
+	if file != "" {
+		viper.SetConfigFile(file)
+	}
+
+	profile := activeProfile
+	applying = true
+	for _, change := range append(changes, removals...) {
+		activeProfile = change.profile
+		change.apply()
+	}
+	applying = false
+	activeProfile = profile
+	return version, nil
+}
+
+func syncFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotateBackups shifts the existing backups (dropping the oldest one) and copies the current file as the newest backup
+func rotateBackups(path string) error {
+	backups := defaultBackups
+	if IsSet(Backups) {
+		backups = GetInt(Backups)
+	}
+	if _, err := os.Stat(path); backups <= 0 || os.IsNotExist(err) {
+		return nil
+	}
+
+	backup := func(n int) string { return fmt.Sprintf("%s.%d", path, n) }
+	if err := os.Remove(backup(backups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for n := backups - 1; n >= 1; n-- {
+		if err := os.Rename(backup(n), backup(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return copyFile(path, backup(1))
+}
+
+// LockFile creates the lock file (<path>.lock) of a file toggl-sync writes (e.g. the configuration file or the sync ledger),
+// waiting (up to lockTimeout) while another process holds it. It returns the function releasing the lock.
+func LockFile(path string) (unlock func(), err error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		} else if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another process (remove %s if no other toggl-sync is running)", path, lock)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestWriteConfigFile(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	writeFile(t, path, "toggl:\n  username: previous\n")
+	viper.SetConfigFile(path)
+	Set(TogglUsername, "current")
+
+	assertSame(t, writeConfigFile(path), nil)
+	assertSame(t, readFile(t, path), "toggl:\n    username: current\n")
+	assertSame(t, readFile(t, path+".1"), "toggl:\n  username: previous\n")
+	info, _ := os.Stat(path)
+	assertSame(t, info.Mode().Perm(), os.FileMode(0600))
+	_, err := os.Stat(path + ".lock")
+	assertSame(t, os.IsNotExist(err), true)
+	Reset()
+}
+
+func TestWriteConfigFile_RotatesBackups(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	writeFile(t, path, "")
+	viper.SetConfigFile(path)
+	Set(Backups, 2)
+
+	for _, username := range []string{"first", "second", "third"} {
+		Set(TogglUsername, username)
+		assertSame(t, writeConfigFile(path), nil)
+	}
+	assertSame(t, readFile(t, path+".1"), "config:\n    backups: 2\ntoggl:\n    username: second\n")
+	assertSame(t, readFile(t, path+".2"), "config:\n    backups: 2\ntoggl:\n    username: first\n")
+	_, err := os.Stat(path + ".3")
+	assertSame(t, os.IsNotExist(err), true)
+	Reset()
+}
+
+func TestWriteConfigFile_NewFile(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	viper.SetConfigFile(path)
+	Set(TogglUsername, "current")
+
+	assertSame(t, writeConfigFile(path), nil)
+	assertSame(t, readFile(t, path), "toggl:\n    username: current\n")
+	_, err := os.Stat(path + ".1")
+	assertSame(t, os.IsNotExist(err), true)
+	Reset()
+}
+
+func TestWriteConfigFile_Locked(t *testing.T) {
+	Reset()
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = 200 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	writeFile(t, path, "toggl:\n  username: previous\n")
+	writeFile(t, path+".lock", "12345\n")
+	viper.SetConfigFile(path)
+	Set(TogglUsername, "current")
+
+	if err := writeConfigFile(path); err == nil {
+		t.Errorf("Writing a locked configuration file should fail")
+	}
+	assertSame(t, readFile(t, path), "toggl:\n  username: previous\n")
+
+	// Locks left behind by a process that crashed are ignored after a while
+	stale := time.Now().Add(-staleLockAge - time.Minute)
+	_ = os.Chtimes(path+".lock", stale, stale)
+	assertSame(t, writeConfigFile(path), nil)
+	assertSame(t, readFile(t, path), "toggl:\n    username: current\n")
+	Reset()
+}
+
+func TestWriteConfigFile_KeepsChangesSavedInTheMeantime(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	writeFile(t, path, "toggl:\n  username: previous\n  password: secret\n")
+	viper.SetConfigFile(path)
+	_ = viper.ReadInConfig()
+	Set(TogglUsername, "current")
+	Unset(TogglPassword)
+	SetOverheadMapping(OverheadMapping{Project: "Meetings", Ticket: "MGMT-1"})
+
+	// Another process saves its own changes after the file was read
+	writeFile(t, path, "jira:\n  overheads:\n  - project: Support\n    ticket: SUP-1\n  username: jiraUser\ntoggl:\n  username: previous\n  password: secret\n")
+
+	assertSame(t, writeConfigFile(path), nil)
+	assertSame(t, Get(TogglUsername), "current")
+	assertSame(t, IsSet(TogglPassword), false)
+	assertSame(t, Get(JiraUsername), "jiraUser")
+	assertSame(t, len(GetOverheadMappings()), 2)
+	assertSame(t, readFile(t, path), "jira:\n    overheads:\n        - project: Support\n          ticket: SUP-1\n        - project: Meetings\n          ticket: MGMT-1\n    username: jiraUser\ntoggl:\n    username: current\n")
+	Reset()
+}
+
+func TestWriteConfigFile_BackupsAreGlobal(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "toggl-sync.yaml")
+	writeFile(t, path, "")
+	viper.SetConfigFile(path)
+	UseProfile("clienta")
+	Set(Backups, 1)
+
+	for _, username := range []string{"first", "second"} {
+		Set(TogglUsername, username)
+		assertSame(t, writeConfigFile(path), nil)
+	}
+	assertSame(t, viper.GetInt(Backups), 1)
+	_, err := os.Stat(path + ".2")
+	assertSame(t, os.IsNotExist(err), true)
+	Reset()
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
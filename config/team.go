@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Origins of config values (see Origin)
+const (
+	OriginPersonal = "personal"
+	OriginTeam     = "team"
+)
+
+const defaultTeamConfigCacheTTL = time.Hour
+
+// teamConfigKeys lists the keys a team config file can supply; any other key in the file is ignored
+var teamConfigKeys = []string{JiraProjectKey, OverheadMappings}
+
+// team holds the values of the team config file, if one was loaded (see LoadTeamConfig)
+var team *viper.Viper
+
+// LoadTeamConfig reads the team config file referenced by TeamConfigURL (a path, or a file:// or http(s):// URL),
+// so its values are used whenever the personal config has none. Remote files are cached locally (see TeamConfigCacheTTL).
+// It returns a description of where the values were read from (empty if no team config file is referenced).
+func LoadTeamConfig() (origin string, err error) {
+	team = nil
+	location := Get(TeamConfigURL)
+	if location == "" {
+		return "", nil
+	}
+
+	var content []byte
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		content, origin, err = readRemoteTeamConfig(location)
+	} else {
+		content, origin, err = readLocalTeamConfig(location)
+	}
+	if err != nil {
+		return origin, err
+	}
+
+	values := viper.New()
+	values.SetConfigType("yaml")
+	if err := values.ReadConfig(bytes.NewReader(content)); err != nil {
+		return origin, fmt.Errorf("error parsing %s: %s", origin, err)
+	}
+	team = values
+	return origin, nil
+}
+
+// Origin returns where the value of the key comes from: OriginPersonal, OriginTeam, or an empty string if it has no value
+func Origin(key string) string {
+	if viper.IsSet(profileKey(key)) {
+		return OriginPersonal
+	} else if teamValues(key) != nil {
+		return OriginTeam
+	}
+	return ""
+}
+
+// OverriddenTeamValues describes the values of the team config file that are overridden by the personal config
+func OverriddenTeamValues() []string {
+	if team == nil {
+		return nil
+	}
+
+	var overridden []string
+	for _, key := range teamConfigKeys {
+		if key != OverheadMappings && team.IsSet(key) && viper.IsSet(profileKey(key)) {
+			overridden = append(overridden, fmt.Sprintf("[%s]", key))
+		}
+	}
+	personal := personalOverheadMappings()
+	for _, mapping := range teamOverheadMappings() {
+		if i := indexOfOverheadMapping(personal, mapping.ProjectID, mapping.Project); i != -1 && personal[i].Ticket != mapping.Ticket {
+			overridden = append(overridden, fmt.Sprintf("the overhead ticket of project [%s]", mapping.Project))
+		}
+	}
+	return overridden
+}
+
+// teamValues returns the team config values when they supply the key, and the personal config does not
+func teamValues(key string) *viper.Viper {
+	if team == nil || viper.IsSet(profileKey(key)) || !team.IsSet(key) {
+		return nil
+	}
+	for _, teamKey := range teamConfigKeys {
+		if key == teamKey {
+			return team
+		}
+	}
+	return nil
+}
+
+func teamOverheadMappings() []OverheadMapping {
+	mappings := make([]OverheadMapping, 0)
+	if team != nil {
+		_ = team.UnmarshalKey(OverheadMappings, &mappings)
+	}
+	for i := range mappings {
+		mappings[i].Origin = OriginTeam
+	}
+	return mappings
+}
+
+// readLocalTeamConfig reads the file; relative paths are resolved against the directory of the personal config file
+func readLocalTeamConfig(location string) ([]byte, string, error) {
+	path := strings.TrimPrefix(location, "file://")
+	if !filepath.IsAbs(path) && FileUsed() != "" {
+		path = filepath.Join(filepath.Dir(FileUsed()), path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, path, fmt.Errorf("error reading %s: %s", path, err)
+	}
+	return content, path, nil
+}
+
+// readRemoteTeamConfig reads the cached copy of the file while it is fresh, and fetches it again otherwise.
+// A stale copy is used when fetching fails, so commands keep working offline.
+func readRemoteTeamConfig(url string) ([]byte, string, error) {
+	ttl := defaultTeamConfigCacheTTL
+	if IsSet(TeamConfigCacheTTL) {
+		var err error
+		if ttl, err = time.ParseDuration(Get(TeamConfigCacheTTL)); err != nil {
+			return nil, url, fmt.Errorf("invalid value for [%s]: %s", TeamConfigCacheTTL, err)
+		}
+	}
+	cachePath, err := teamConfigCachePath(url)
+	if err != nil {
+		return nil, url, err
+	}
+
+	if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < ttl {
+		if content, err := os.ReadFile(cachePath); err == nil {
+			return content, fmt.Sprintf("%s (cached copy at %s)", url, cachePath), nil
+		}
+	}
+
+	content, fetchErr := fetchTeamConfig(url)
+	if fetchErr == nil {
+		// Caching is best effort: the values fetched are used anyway
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0700); err == nil {
+			_ = os.WriteFile(cachePath, content, 0600)
+		}
+		return content, url, nil
+	}
+
+	content, err = os.ReadFile(cachePath)
+	if err != nil {
+		return nil, url, fmt.Errorf("error fetching %s: %s", url, fetchErr)
+	}
+	return content, fmt.Sprintf("%s (stale copy at %s, as fetching failed: %s)", url, cachePath, fetchErr), nil
+}
+
+func fetchTeamConfig(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("request failed with status: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// teamConfigCachePath returns the path of the cached copy of the file, unique per URL
+func teamConfigCachePath(url string) (string, error) {
+	dir := Get(TeamConfigCacheDir)
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("unable to find a cache directory (set [%s] to use a specific one): %s", TeamConfigCacheDir, err)
+		}
+		dir = filepath.Join(cacheDir, "toggl-sync")
+	}
+	hash := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, fmt.Sprintf("team-config-%s.yaml", hex.EncodeToString(hash[:8]))), nil
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const teamConfigContent = `
+jira:
+  project:
+    key: [ENG, OPS]
+  server:
+    url: http://ignored
+  overheads:
+    - projectId: 7
+      project: Meetings
+      ticket: MGMT-1
+    - project: Support
+      ticket: OPS-7
+`
+
+func TestLoadTeamConfig_LocalFile(t *testing.T) {
+	Reset()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), teamConfigContent)
+	viper.SetConfigFile(filepath.Join(dir, "toggl-sync.yaml"))
+	Set(TeamConfigURL, "team.yaml")
+
+	origin, err := LoadTeamConfig()
+	assertSame(t, err, nil)
+	assertSame(t, origin, filepath.Join(dir, "team.yaml"))
+	assertSameSlice(t, GetSlice(JiraProjectKey), []string{"ENG", "OPS"})
+	assertSame(t, Origin(JiraProjectKey), OriginTeam)
+	assertSame(t, Get(JiraServerURL), "")
+	assertSame(t, GetOverheadTicket(7, "Team meetings"), "MGMT-1")
+	assertSameSlice(t, Keys(), []string{"jira.overheads", "jira.project.key", "team.config.url"})
+	Reset()
+}
+
+func TestLoadTeamConfig_PersonalConfigOverrides(t *testing.T) {
+	Reset()
+	path := filepath.Join(t.TempDir(), "team.yaml")
+	writeFile(t, path, teamConfigContent)
+	Set(TeamConfigURL, "file://"+path)
+	Set(JiraProjectKey, []string{"ENG"})
+	SetOverheadMapping(OverheadMapping{Project: "Support", Ticket: "OPS-8"})
+
+	_, err := LoadTeamConfig()
+	assertSame(t, err, nil)
+	assertSameSlice(t, GetSlice(JiraProjectKey), []string{"ENG"})
+	assertSame(t, Origin(JiraProjectKey), OriginPersonal)
+	mappings := GetOverheadMappings()
+	if !reflect.DeepEqual(mappings, []OverheadMapping{
+		{Project: "Support", Ticket: "OPS-8"},
+		{ProjectID: 7, Project: "Meetings", Ticket: "MGMT-1", Origin: OriginTeam},
+	}) {
+		t.Errorf("Unexpected overhead mappings: %v", mappings)
+	}
+	assertSameSlice(t, OverriddenTeamValues(), []string{"[jira.project.key]", "the overhead ticket of project [Support]"})
+
+	// Team mappings are never copied into the personal config
+	SetOverheadMapping(OverheadMapping{Project: "Hiring", Ticket: "MGMT-2"})
+	assertSame(t, len(personalOverheadMappings()), 2)
+	assertSame(t, RemoveOverheadMapping(7, "Meetings"), false)
+	Reset()
+}
+
+func TestLoadTeamConfig_NotReferenced(t *testing.T) {
+	Reset()
+	origin, err := LoadTeamConfig()
+	assertSame(t, err, nil)
+	assertSame(t, origin, "")
+}
+
+func TestLoadTeamConfig_MissingFile(t *testing.T) {
+	Reset()
+	Set(TeamConfigURL, filepath.Join(t.TempDir(), "missing.yaml"))
+
+	_, err := LoadTeamConfig()
+	if err == nil {
+		t.Errorf("Reading a missing team config file should fail")
+	}
+	Reset()
+}
+
+func TestLoadTeamConfig_Remote(t *testing.T) {
+	Reset()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(teamConfigContent))
+	}))
+	Set(TeamConfigURL, server.URL+"/team.yaml")
+	Set(TeamConfigCacheDir, t.TempDir())
+
+	// Fetched once, and read from the cache afterwards
+	origin, err := LoadTeamConfig()
+	assertSame(t, err, nil)
+	assertSame(t, origin, server.URL+"/team.yaml")
+	origin, err = LoadTeamConfig()
+	assertSame(t, err, nil)
+	assertSame(t, strings.Contains(origin, "cached copy"), true)
+	assertSame(t, requests, 1)
+
+	// Stale copies are used when fetching fails
+	cachePath, _ := teamConfigCachePath(server.URL + "/team.yaml")
+	stale := time.Now().Add(-2 * time.Hour)
+	_ = os.Chtimes(cachePath, stale, stale)
+	server.Close()
+	origin, err = LoadTeamConfig()
+	assertSame(t, err, nil)
+	assertSame(t, strings.Contains(origin, "stale copy"), true)
+	assertSame(t, GetOverheadTicket(0, "support"), "OPS-7")
+	Reset()
+}
+
+func TestLoadTeamConfig_RemoteErrorWithoutCache(t *testing.T) {
+	Reset()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	Set(TeamConfigURL, server.URL+"/team.yaml")
+	Set(TeamConfigCacheDir, t.TempDir())
+
+	_, err := LoadTeamConfig()
+	if err == nil {
+		t.Errorf("Fetching a team config file should fail when there is no cached copy")
+	}
+	Reset()
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,213 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+)
+
+const defaultLedgerPath = "/usr/local/etc/toggl-sync.ledger.json"
+
+// Ledger keeps track of the days (and worklogs) already synced, so they are not synced twice.
+type Ledger interface {
+	Get(date string) (*Record, error)
+	All() ([]Record, error)
+	Save(record Record) error
+}
+
+// Record contains the worklogs synced for a given day (and profile).
+// A record is complete when every worklog of the day was synced without errors.
+type Record struct {
+	Profile  string    `json:"profile,omitempty"`
+	Date     string    `json:"date"`
+	Complete bool      `json:"complete"`
+	SyncedAt time.Time `json:"syncedAt"`
+	Worklogs []Worklog `json:"worklogs"`
+}
+
+// Worklog contains the details of a worklog successfully written to a sink.
+type Worklog struct {
+	Sink        string `json:"sink"`
+	Ticket      string `json:"ticket"`
+	Description string `json:"description"`
+	Seconds     int    `json:"seconds"`
+	EntryIDs    []int  `json:"entryIds,omitempty"`
+	WorklogID   string `json:"worklogId,omitempty"`
+	Partial     bool   `json:"partial,omitempty"`
+}
+
+// Find returns the worklog previously written to the sink for the same ticket and description, if any exists
+func (record *Record) Find(sink string, ticket string, description string) (Worklog, bool) {
+	for _, worklog := range record.Worklogs {
+		if worklog.Sink == sink && worklog.Ticket == ticket && worklog.Description == description {
+			return worklog, true
+		}
+	}
+	return Worklog{}, false
+}
+
+// Synced returns the worklogs written to the sink for any of the given time entries.
+// Worklogs recorded without entry ids (or for entries without ids) are matched by ticket and description instead.
+func (record *Record) Synced(sink string, ticket string, description string, entryIDs []int) []Worklog {
+	var synced []Worklog
+	for _, worklog := range record.Worklogs {
+		if worklog.Sink != sink {
+			continue
+		}
+		if len(worklog.EntryIDs) != 0 && len(entryIDs) != 0 {
+			if sharesEntries(worklog.EntryIDs, entryIDs) {
+				synced = append(synced, worklog)
+			}
+		} else if worklog.Ticket == ticket && worklog.Description == description {
+			synced = append(synced, worklog)
+		}
+	}
+	return synced
+}
+
+func sharesEntries(a []int, b []int) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FindEntry returns the index of the worklog written to the sink for the given time entry (-1 if there is none)
+func (record *Record) FindEntry(sink string, entryID int) int {
+	for i, worklog := range record.Worklogs {
+		if worklog.Sink != sink {
+			continue
+		}
+		for _, id := range worklog.EntryIDs {
+			if id == entryID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// FileLedger is an implementation of Ledger that stores all records in a JSON file.
+type FileLedger struct{}
+
+// NewFileLedger creates a new ledger stored in the file configured in config.LedgerPath (or a default location).
+// Records are kept per profile (see config.ActiveProfile).
+func NewFileLedger() Ledger {
+	return &FileLedger{}
+}
+
+// Get returns the record of the given day for the active profile (nil if the day was never synced)
+func (fileLedger *FileLedger) Get(date string) (*Record, error) {
+	records, err := fileLedger.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		if records[i].Profile == config.ActiveProfile() && records[i].Date == date {
+			return &records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// All returns every record of the active profile
+func (fileLedger *FileLedger) All() ([]Record, error) {
+	records, err := fileLedger.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var profileRecords []Record
+	for _, record := range records {
+		if record.Profile == config.ActiveProfile() {
+			profileRecords = append(profileRecords, record)
+		}
+	}
+	return profileRecords, nil
+}
+
+// Save stores the record for the active profile, replacing any previous record of the same day.
+// The ledger file is locked while saving, as the daemon, webhooks and the UI may save records at the same time.
+func (fileLedger *FileLedger) Save(record Record) error {
+	unlock, err := config.LockFile(fileLedger.path())
+	if err != nil {
+		return fmt.Errorf("[Ledger] Locking file failed! Error: %s", err)
+	}
+	defer unlock()
+
+	records, err := fileLedger.readAll()
+	if err != nil {
+		return err
+	}
+
+	record.Profile = config.ActiveProfile()
+	replaced := false
+	for i := range records {
+		if records[i].Profile == record.Profile && records[i].Date == record.Date {
+			records[i] = record
+			replaced = true
+		}
+	}
+	if !replaced {
+		records = append(records, record)
+	}
+
+	return fileLedger.writeAll(records)
+}
+
+func (fileLedger *FileLedger) readAll() ([]Record, error) {
+	bytes, err := os.ReadFile(fileLedger.path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("[Ledger] Reading file failed! Error: %s", err)
+	}
+
+	var records []Record
+	if err = json.Unmarshal(bytes, &records); err != nil {
+		return nil, fmt.Errorf("[Ledger] Error unmarshalling file contents: %s", err)
+	}
+	return records, nil
+}
+
+func (fileLedger *FileLedger) writeAll(records []Record) error {
+	bytes, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[Ledger] Marshalling of records failed! Error: %s", err)
+	}
+
+	path := fileLedger.path()
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("[Ledger] Writing file failed! Error: %s", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(bytes); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("[Ledger] Writing file failed! Error: %s", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("[Ledger] Writing file failed! Error: %s", err)
+	}
+	if err = os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("[Ledger] Writing file failed! Error: %s", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (fileLedger *FileLedger) path() string {
+	if path := config.Get(config.LedgerPath); path != "" {
+		return path
+	}
+	return defaultLedgerPath
+}
@@ -0,0 +1,137 @@
+package ledger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLedger_GetUnknownDate(t *testing.T) {
+	config.Reset()
+	config.Set(config.LedgerPath, filepath.Join(t.TempDir(), "ledger.json"))
+
+	record, err := NewFileLedger().Get("2020-05-22")
+	assert.Nil(t, err)
+	assert.Nil(t, record)
+}
+
+func TestFileLedger_SaveAndGet(t *testing.T) {
+	config.Reset()
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	config.Set(config.LedgerPath, path)
+
+	fileLedger := NewFileLedger()
+	assert.Nil(t, fileLedger.Save(Record{
+		Date:     "2020-05-22",
+		Worklogs: []Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 60}},
+	}))
+	assert.Nil(t, fileLedger.Save(Record{Date: "2020-05-23", Complete: true}))
+	assert.Nil(t, fileLedger.Save(Record{
+		Date:     "2020-05-22",
+		Complete: true,
+		Worklogs: []Worklog{{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 120}},
+	}))
+
+	record, err := fileLedger.Get("2020-05-22")
+	assert.Nil(t, err)
+	assert.True(t, record.Complete)
+	worklog, ok := record.Find("jira", "ENG-1001", "ENG-1001")
+	assert.True(t, ok)
+	assert.Equal(t, 120, worklog.Seconds)
+	_, ok = record.Find("csv", "ENG-1001", "ENG-1001")
+	assert.False(t, ok)
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFileLedger_RecordsArePerProfile(t *testing.T) {
+	config.Reset()
+	config.Set(config.LedgerPath, filepath.Join(t.TempDir(), "ledger.json"))
+	ledgerPath := config.Get(config.LedgerPath)
+
+	fileLedger := NewFileLedger()
+	config.UseProfile("clienta")
+	config.Set(config.LedgerPath, ledgerPath)
+	assert.Nil(t, fileLedger.Save(Record{Date: "2020-05-22", Complete: true}))
+
+	config.UseProfile("clientb")
+	config.Set(config.LedgerPath, ledgerPath)
+	record, err := fileLedger.Get("2020-05-22")
+	assert.Nil(t, err)
+	assert.Nil(t, record)
+	config.Reset()
+}
+
+func TestFileLedger_ErrorWhenFileIsCorrupted(t *testing.T) {
+	config.Reset()
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	assert.Nil(t, os.WriteFile(path, []byte("Bogus!"), 0600))
+	config.Set(config.LedgerPath, path)
+
+	_, err := NewFileLedger().Get("2020-05-22")
+	assert.NotNil(t, err)
+}
+
+func TestFileLedger_AllAndFindEntry(t *testing.T) {
+	config.Reset()
+	config.Set(config.LedgerPath, filepath.Join(t.TempDir(), "ledger.json"))
+
+	fileLedger := NewFileLedger()
+	assert.Nil(t, fileLedger.Save(Record{Date: "2020-05-22"}))
+	assert.Nil(t, fileLedger.Save(Record{
+		Date: "2020-05-23",
+		Worklogs: []Worklog{
+			{Sink: "csv", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 60, EntryIDs: []int{7}},
+			{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001", Seconds: 60, EntryIDs: []int{7}, WorklogID: "10001"},
+		},
+	}))
+
+	records, err := fileLedger.All()
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, -1, records[0].FindEntry("jira", 7))
+	assert.Equal(t, 1, records[1].FindEntry("jira", 7))
+	assert.Equal(t, "10001", records[1].Worklogs[1].WorklogID)
+}
+
+func TestFileLedger_ConcurrentSaves(t *testing.T) {
+	config.Reset()
+	config.Set(config.LedgerPath, filepath.Join(t.TempDir(), "ledger.json"))
+
+	fileLedger := NewFileLedger()
+	var wg sync.WaitGroup
+	for day := 1; day <= 10; day++ {
+		wg.Add(1)
+		go func(day int) {
+			defer wg.Done()
+			assert.Nil(t, fileLedger.Save(Record{Date: fmt.Sprintf("2020-05-%02d", day), Complete: true}))
+		}(day)
+	}
+	wg.Wait()
+
+	records, err := fileLedger.All()
+	assert.Nil(t, err)
+	assert.Len(t, records, 10, "No save should be lost")
+}
+
+func TestRecord_Synced(t *testing.T) {
+	record := Record{Worklogs: []Worklog{
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001 Review", Seconds: 60, EntryIDs: []int{1, 2}},
+		{Sink: "jira", Ticket: "ENG-1001", Description: "ENG-1001 Review; ENG-1001 Fixes", Seconds: 30, EntryIDs: []int{1, 2, 3}},
+		{Sink: "csv", Ticket: "ENG-1001", Description: "ENG-1001 Review", Seconds: 60, EntryIDs: []int{1, 2}},
+		{Sink: "jira", Ticket: "ENG-1002", Description: "ENG-1002", Seconds: 120},
+	}}
+
+	assert.Equal(t, record.Worklogs[:2], record.Synced("jira", "ENG-1001", "ENG-1001 Review; ENG-1001 Fixes; ENG-1001 Tests", []int{1, 2, 3, 4}))
+	assert.Empty(t, record.Synced("jira", "ENG-1001", "ENG-1001 Review", []int{4}))
+	// Worklogs recorded without entry ids are matched by ticket and description
+	assert.Equal(t, record.Worklogs[3:], record.Synced("jira", "ENG-1002", "ENG-1002", []int{5}))
+	assert.Empty(t, record.Synced("jira", "ENG-1002", "ENG-1002 Fixes", []int{5}))
+}
@@ -6,14 +6,47 @@ import (
 	"github.com/javicg/toggl-sync/api"
 	"github.com/javicg/toggl-sync/cmd"
 	"github.com/javicg/toggl-sync/config"
+	"github.com/javicg/toggl-sync/ledger"
+	"github.com/javicg/toggl-sync/schedule"
+	"github.com/javicg/toggl-sync/sink"
+	"github.com/javicg/toggl-sync/source"
 )
 
 func main() {
 	configManager := &config.ViperConfigManager{}
 	inputCtrl := cmd.StdInController{}
 
-	rootCmd := cmd.NewRootCmd(configManager, inputCtrl, api.NewTogglAPI(), api.NewJiraAPI())
+	togglAPI := api.NewTogglAPI()
+	jiraAPI := api.NewJiraRouter(api.NewJiraAPI(), api.NewJiraAPIForInstance)
+
+	sinks := map[string]sink.WorklogSink{
+		sink.Jira:  sink.NewJiraSink(jiraAPI),
+		sink.CSV:   sink.NewCSVSink(),
+		sink.JSON:  sink.NewJSONSink(),
+		sink.Tempo: sink.NewTempoSink(api.NewTempoAPI()),
+	}
+
+	sources := map[string]source.TimeSource{
+		source.Toggl:    togglAPI,
+		source.Clockify: source.NewClockifySource(api.NewClockifyAPI()),
+		source.CSV:      source.NewCSVSource(),
+		source.ICS:      source.NewICSSource(),
+	}
+
+	syncLedger := ledger.NewFileLedger()
+
+	rootCmd := cmd.NewRootCmd(configManager, inputCtrl, sources, sinks, syncLedger)
+	rootCmd.AddCommand(cmd.NewConfigCmd(configManager))
 	rootCmd.AddCommand(cmd.NewConfigureCmd(configManager, inputCtrl))
+	rootCmd.AddCommand(cmd.NewDoctorCmd(configManager, sources, api.NewJiraAPIForInstance))
+	rootCmd.AddCommand(cmd.NewDiffCmd(configManager, sources, jiraAPI))
+	rootCmd.AddCommand(cmd.NewDaemonCmd(configManager, sources, sinks, syncLedger, schedule.SystemClock{}))
+	rootCmd.AddCommand(cmd.NewOverheadCmd(configManager, togglAPI))
+	rootCmd.AddCommand(cmd.NewProfileCmd(configManager))
+	rootCmd.AddCommand(cmd.NewReportCmd(configManager, sources))
+	rootCmd.AddCommand(cmd.NewServeCmd(configManager, sources[source.Toggl], jiraAPI, syncLedger))
+	rootCmd.AddCommand(cmd.NewStatusCmd(configManager, sources, syncLedger, schedule.SystemClock{}))
+	rootCmd.AddCommand(cmd.NewUICmd(configManager, sources, sinks, syncLedger))
 	rootCmd.AddCommand(cmd.NewVersionCmd())
 
 	if err := rootCmd.Execute(); err != nil {
@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Cron(t *testing.T) {
+	schedule, err := Parse("0 18 * * 1-5")
+	assert.Nil(t, err)
+
+	// 2020-05-22 is a Friday
+	assert.Equal(t, at("2020-05-22T18:00"), schedule.Next(at("2020-05-22T09:00")))
+	assert.Equal(t, at("2020-05-25T18:00"), schedule.Next(at("2020-05-22T18:00")))
+}
+
+func TestParse_CronSteps(t *testing.T) {
+	schedule, err := Parse("*/15 9-10 * * *")
+	assert.Nil(t, err)
+
+	assert.Equal(t, at("2020-05-22T09:15"), schedule.Next(at("2020-05-22T09:00")))
+	assert.Equal(t, at("2020-05-23T09:00"), schedule.Next(at("2020-05-22T10:45")))
+}
+
+func TestParse_CronDayOfMonthOrDayOfWeek(t *testing.T) {
+	schedule, err := Parse("0 12 1 * 0")
+	assert.Nil(t, err)
+
+	// 2020-05-24 is a Sunday; 2020-06-01 is the first day of the month
+	assert.Equal(t, at("2020-05-24T12:00"), schedule.Next(at("2020-05-22T12:00")))
+	assert.Equal(t, at("2020-05-31T12:00"), schedule.Next(at("2020-05-24T12:00")))
+	assert.Equal(t, at("2020-06-01T12:00"), schedule.Next(at("2020-05-31T12:00")))
+}
+
+func TestParse_Natural(t *testing.T) {
+	schedule, err := Parse("Every weekday at 18:30")
+	assert.Nil(t, err)
+	assert.Equal(t, at("2020-05-25T18:30"), schedule.Next(at("2020-05-22T19:00")))
+
+	schedule, err = Parse("every day at 9:05")
+	assert.Nil(t, err)
+	assert.Equal(t, at("2020-05-23T09:05"), schedule.Next(at("2020-05-22T19:00")))
+
+	schedule, err = Parse("every sunday at 07:00")
+	assert.Nil(t, err)
+	assert.Equal(t, at("2020-05-24T07:00"), schedule.Next(at("2020-05-22T19:00")))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "every weekday", "0 18 * *", "60 18 * * *", "0 18 * * mon", "*/0 * * * *", "5-1 * * * *"} {
+		_, err := Parse(expression)
+		assert.NotNil(t, err, "Expression [%s] should be invalid", expression)
+	}
+}
+
+func TestActivations(t *testing.T) {
+	schedule, _ := Parse("every weekday at 18:00")
+
+	activations := Activations(schedule, at("2020-05-21T12:00"), at("2020-05-26T12:00"))
+	assert.Equal(t, []time.Time{at("2020-05-21T18:00"), at("2020-05-22T18:00"), at("2020-05-25T18:00")}, activations)
+}
+
+func at(value string) time.Time {
+	t, _ := time.Parse("2006-01-02T15:04", value)
+	return t
+}
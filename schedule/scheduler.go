@@ -0,0 +1,57 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Clock isolates the scheduler from the system time, so it can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is an implementation of Clock that relies on the system time.
+type SystemClock struct{}
+
+// Now returns the current system time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the current time on the returned channel
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Scheduler runs a job at every activation of a Schedule.
+type Scheduler struct {
+	clock    Clock
+	schedule Schedule
+}
+
+// NewScheduler creates a new Scheduler using the provided clock and schedule.
+func NewScheduler(clock Clock, schedule Schedule) *Scheduler {
+	return &Scheduler{clock: clock, schedule: schedule}
+}
+
+// Run blocks, running the job at every activation of the schedule, until the context is cancelled
+func (scheduler *Scheduler) Run(ctx context.Context, job func(activation time.Time)) {
+	for {
+		now := scheduler.clock.Now()
+		next := scheduler.schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-scheduler.clock.After(next.Sub(now)):
+			if ctx.Err() != nil {
+				return
+			}
+			job(next)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	clock := &FakeClock{now: at("2020-05-22T09:00"), maxTicks: 3, cancel: cancel}
+	schedule, _ := Parse("every weekday at 18:00")
+
+	var activations []time.Time
+	NewScheduler(clock, schedule).Run(ctx, func(activation time.Time) {
+		activations = append(activations, activation)
+	})
+
+	assert.Equal(t, []time.Time{at("2020-05-22T18:00"), at("2020-05-25T18:00"), at("2020-05-26T18:00")}, activations)
+}
+
+func TestScheduler_Run_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	schedule, _ := Parse("every day at 18:00")
+
+	NewScheduler(SystemClock{}, schedule).Run(ctx, func(time.Time) {
+		t.Fatal("no job should run once cancelled")
+	})
+}
+
+type FakeClock struct {
+	now      time.Time
+	ticks    int
+	maxTicks int
+	cancel   func()
+}
+
+func (clock *FakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *FakeClock) After(d time.Duration) <-chan time.Time {
+	if clock.ticks == clock.maxTicks {
+		clock.cancel()
+		return make(chan time.Time)
+	}
+	clock.ticks++
+	clock.now = clock.now.Add(d)
+	c := make(chan time.Time, 1)
+	c <- clock.now
+	return c
+}
@@ -0,0 +1,102 @@
+package sink
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/javicg/toggl-sync/config"
+)
+
+// CSVSink is an implementation of WorklogSink that appends every worklog as a row of a CSV file.
+type CSVSink struct{}
+
+// NewCSVSink creates a new sink writing to the CSV file configured in config.CSVSinkPath.
+func NewCSVSink() WorklogSink {
+	return &CSVSink{}
+}
+
+var csvHeader = []string{"date", "ticket", "seconds", "description", "project", "overhead"}
+
+// Write appends the worklog to the CSV file, creating the file (and header) if it does not exist yet
+func (*CSVSink) Write(worklog Worklog) error {
+	path := config.Get(config.CSVSinkPath)
+	if path == "" {
+		return fmt.Errorf("[CSVSink] No file configured! Please, set [%s] in the configuration file", config.CSVSinkPath)
+	}
+
+	_, err := os.Stat(path)
+	isNewFile := os.IsNotExist(err)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("[CSVSink] Opening file failed! Error: %s", err)
+	}
+
+	w := csv.NewWriter(f)
+	if isNewFile {
+		_ = w.Write(csvHeader)
+	}
+	_ = w.Write([]string{
+		worklog.Date,
+		worklog.Ticket,
+		strconv.Itoa(int(worklog.TimeSpent.Seconds())),
+		worklog.Description,
+		worklog.Project,
+		strconv.FormatBool(worklog.Overhead),
+	})
+	w.Flush()
+	if err = w.Error(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("[CSVSink] Writing worklog failed! Error: %s", err)
+	}
+
+	return f.Close()
+}
+
+// JSONSink is an implementation of WorklogSink that appends every worklog as a JSON object (one per line) to a file.
+type JSONSink struct{}
+
+// NewJSONSink creates a new sink writing to the JSON Lines file configured in config.JSONSinkPath.
+func NewJSONSink() WorklogSink {
+	return &JSONSink{}
+}
+
+type jsonWorklog struct {
+	Date        string `json:"date"`
+	Ticket      string `json:"ticket"`
+	Seconds     int    `json:"seconds"`
+	Description string `json:"description"`
+	Project     string `json:"project,omitempty"`
+	Overhead    bool   `json:"overhead"`
+}
+
+// Write appends the worklog to the JSON Lines file, creating the file if it does not exist yet
+func (*JSONSink) Write(worklog Worklog) error {
+	path := config.Get(config.JSONSinkPath)
+	if path == "" {
+		return fmt.Errorf("[JSONSink] No file configured! Please, set [%s] in the configuration file", config.JSONSinkPath)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("[JSONSink] Opening file failed! Error: %s", err)
+	}
+
+	err = json.NewEncoder(f).Encode(jsonWorklog{
+		Date:        worklog.Date,
+		Ticket:      worklog.Ticket,
+		Seconds:     int(worklog.TimeSpent.Seconds()),
+		Description: worklog.Description,
+		Project:     worklog.Project,
+		Overhead:    worklog.Overhead,
+	})
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("[JSONSink] Writing worklog failed! Error: %s", err)
+	}
+
+	return f.Close()
+}
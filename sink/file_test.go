@@ -0,0 +1,79 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var testWorklogs = []Worklog{
+	{
+		Date:        "2020-05-22",
+		Ticket:      "ENG-1001",
+		TimeSpent:   time.Duration(120) * time.Second,
+		Description: "ENG-1001",
+	},
+	{
+		Date:        "2020-05-22",
+		Ticket:      "MGMT-1",
+		TimeSpent:   time.Duration(60) * time.Second,
+		Description: "Team catch-up, weekly",
+		Project:     "Meetings",
+		Overhead:    true,
+	},
+}
+
+func TestCSVSink(t *testing.T) {
+	config.Reset()
+	defer config.Reset()
+	path := filepath.Join(t.TempDir(), "timesheet.csv")
+	config.Set(config.CSVSinkPath, path)
+
+	csvSink := NewCSVSink()
+	for _, worklog := range testWorklogs {
+		assert.Nil(t, csvSink.Write(worklog))
+	}
+
+	contents, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "date,ticket,seconds,description,project,overhead\n"+
+		"2020-05-22,ENG-1001,120,ENG-1001,,false\n"+
+		"2020-05-22,MGMT-1,60,\"Team catch-up, weekly\",Meetings,true\n", string(contents))
+}
+
+func TestCSVSink_ErrorWhenPathNotConfigured(t *testing.T) {
+	config.Reset()
+	defer config.Reset()
+
+	err := NewCSVSink().Write(testWorklogs[0])
+	assert.NotNil(t, err)
+}
+
+func TestJSONSink(t *testing.T) {
+	config.Reset()
+	defer config.Reset()
+	path := filepath.Join(t.TempDir(), "timesheet.json")
+	config.Set(config.JSONSinkPath, path)
+
+	jsonSink := NewJSONSink()
+	for _, worklog := range testWorklogs {
+		assert.Nil(t, jsonSink.Write(worklog))
+	}
+
+	contents, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"date":"2020-05-22","ticket":"ENG-1001","seconds":120,"description":"ENG-1001","overhead":false}`+"\n"+
+		`{"date":"2020-05-22","ticket":"MGMT-1","seconds":60,"description":"Team catch-up, weekly","project":"Meetings","overhead":true}`+"\n", string(contents))
+}
+
+func TestJSONSink_ErrorWhenPathNotConfigured(t *testing.T) {
+	config.Reset()
+	defer config.Reset()
+
+	err := NewJSONSink().Write(testWorklogs[0])
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,62 @@
+package sink
+
+import (
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+)
+
+// JiraSink is an implementation of WorklogSink that logs work on Jira.
+type JiraSink struct {
+	jiraAPI api.JiraAPI
+}
+
+// NewJiraSink creates a new sink logging work through the provided Jira API client.
+func NewJiraSink(jiraAPI api.JiraAPI) WorklogSink {
+	return &JiraSink{jiraAPI: jiraAPI}
+}
+
+// Write logs the work on Jira. Overhead work (and work whose description says more than its ticket, e.g. merged entries)
+// keeps the original description as part of the worklog comment. Worklogs with restricted visibility are added with it.
+func (jira *JiraSink) Write(worklog Worklog) error {
+	if options := JiraWorklogOptions(worklog); options.Visibility != nil {
+		_, err := jira.Add(worklog)
+		return err
+	} else if comment := JiraComment(worklog); comment != "" {
+		return jira.jiraAPI.LogWorkWithUserDescription(worklog.Ticket, worklog.Started, worklog.TimeSpent, comment)
+	}
+	return jira.jiraAPI.LogWork(worklog.Ticket, worklog.Started, worklog.TimeSpent)
+}
+
+// Add logs the work on Jira like Write does, returning the id of the new worklog
+func (jira *JiraSink) Add(worklog Worklog) (string, error) {
+	return jira.jiraAPI.AddWorklog(worklog.Ticket, worklog.Started, worklog.TimeSpent, JiraComment(worklog), JiraWorklogOptions(worklog))
+}
+
+// Update replaces the duration (and description) of a worklog previously added to Jira
+func (jira *JiraSink) Update(id string, worklog Worklog) error {
+	return jira.jiraAPI.UpdateWorklog(worklog.Ticket, id, worklog.TimeSpent, JiraComment(worklog), JiraWorklogOptions(worklog))
+}
+
+// JiraComment returns the description worklogs are added with: their comment, if set, or their description
+// (empty for project work described by its ticket alone, which uses the default one)
+func JiraComment(worklog Worklog) string {
+	if worklog.Comment != "" {
+		return worklog.Comment
+	} else if worklog.Overhead || worklog.Description != worklog.Ticket {
+		return worklog.Description
+	}
+	return ""
+}
+
+// JiraWorklogOptions returns the options worklogs are added with, i.e. the visibility restriction in config
+// for their overhead project (if any) or, otherwise, for their ticket (see config.FindVisibilityRule)
+func JiraWorklogOptions(worklog Worklog) api.WorklogOptions {
+	projectID, project := 0, ""
+	if worklog.Overhead {
+		projectID, project = worklog.ProjectID, worklog.Project
+	}
+	if rule, ok := config.FindVisibilityRule(projectID, project, worklog.Ticket); ok {
+		return api.WorklogOptions{Visibility: &api.WorklogVisibility{Type: rule.Type, Value: rule.Value}}
+	}
+	return api.WorklogOptions{}
+}
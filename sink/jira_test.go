@@ -0,0 +1,147 @@
+package sink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJiraSink_ProjectWork(t *testing.T) {
+	jiraAPI := &MockJiraAPI{}
+
+	err := NewJiraSink(jiraAPI).Write(Worklog{
+		Ticket:      "ENG-1001",
+		Started:     time.Date(2020, 5, 22, 8, 30, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		TimeSpent:   time.Duration(60) * time.Second,
+		Description: "ENG-1001",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"LogWork ENG-1001 2020-05-22T08:30:00+02:00 1m0s"}, jiraAPI.Calls)
+}
+
+func TestJiraSink_OverheadWork(t *testing.T) {
+	jiraAPI := &MockJiraAPI{}
+
+	err := NewJiraSink(jiraAPI).Write(Worklog{
+		Ticket:      "MGMT-1",
+		TimeSpent:   time.Duration(60) * time.Second,
+		Description: "Team catch-up",
+		Project:     "Meetings",
+		Overhead:    true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"LogWorkWithUserDescription MGMT-1 0001-01-01T00:00:00Z 1m0s Team catch-up"}, jiraAPI.Calls)
+}
+
+func TestJiraSink_MergedProjectWork(t *testing.T) {
+	jiraAPI := &MockJiraAPI{}
+
+	err := NewJiraSink(jiraAPI).Write(Worklog{
+		Ticket:      "ENG-1001",
+		TimeSpent:   time.Duration(60) * time.Second,
+		Description: "ENG-1001 Review; ENG-1001 Fixes",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"LogWorkWithUserDescription ENG-1001 0001-01-01T00:00:00Z 1m0s ENG-1001 Review; ENG-1001 Fixes"}, jiraAPI.Calls)
+}
+
+func TestJiraSink_RestrictedVisibility(t *testing.T) {
+	config.Reset()
+	config.Set(config.WorklogVisibilities, []map[string]interface{}{
+		{"project": "Interviews", "type": api.VisibilityGroup, "value": "hr"},
+		{"projectId": 7, "type": api.VisibilityGroup, "value": "leads"},
+		{"ticket": "MGMT-2", "type": api.VisibilityRole, "value": "Managers"},
+	})
+	defer config.Reset()
+	jiraAPI := &MockJiraAPI{}
+	jiraSink := NewJiraSink(jiraAPI)
+
+	err := jiraSink.Write(Worklog{Ticket: "MGMT-1", TimeSpent: time.Minute, Description: "Interview", Project: "Interviews", Overhead: true})
+	assert.Nil(t, err)
+	err = jiraSink.Write(Worklog{Ticket: "MGMT-2", TimeSpent: time.Minute, Description: "1:1", Project: "One-on-ones", Overhead: true})
+	assert.Nil(t, err)
+	err = jiraSink.Write(Worklog{Ticket: "MGMT-2", TimeSpent: time.Minute, Description: "Hiring sync", Project: "Hiring", ProjectID: 7, Overhead: true})
+	assert.Nil(t, err)
+	err = jiraSink.Write(Worklog{Ticket: "MGMT-3", TimeSpent: time.Minute, Description: "Team catch-up", Project: "Meetings", Overhead: true})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"AddWorklog MGMT-1 1m0s Interview (group hr)",
+		"AddWorklog MGMT-2 1m0s 1:1 (role Managers)",
+		"AddWorklog MGMT-2 1m0s Hiring sync (group leads)",
+		"LogWorkWithUserDescription MGMT-3 0001-01-01T00:00:00Z 1m0s Team catch-up",
+	}, jiraAPI.Calls)
+}
+
+func TestJiraSink_AddAndUpdate(t *testing.T) {
+	jiraAPI := &MockJiraAPI{}
+	jiraSink := NewJiraSink(jiraAPI).(WorklogUpdater)
+	worklog := Worklog{Ticket: "ENG-1001", TimeSpent: time.Duration(60) * time.Second, Description: "ENG-1001", Partial: true}
+
+	_, err := jiraSink.Add(worklog)
+	assert.Nil(t, err)
+	worklog.TimeSpent, worklog.Partial = time.Duration(120)*time.Second, false
+	err = jiraSink.Update("10042", worklog)
+	assert.Nil(t, err)
+
+	// Project work is logged with the default description
+	assert.Equal(t, []string{"AddWorklog ENG-1001 1m0s ", "UpdateWorklog ENG-1001 10042 2m0s "}, jiraAPI.Calls)
+}
+
+type MockJiraAPI struct {
+	Calls []string
+}
+
+func (mock *MockJiraAPI) LogWork(ticket string, started time.Time, timeSpent time.Duration) error {
+	mock.Calls = append(mock.Calls, "LogWork "+ticket+" "+started.Format(time.RFC3339)+" "+timeSpent.String())
+	return nil
+}
+
+func (mock *MockJiraAPI) LogWorkWithUserDescription(ticket string, started time.Time, timeSpent time.Duration, description string) error {
+	mock.Calls = append(mock.Calls, "LogWorkWithUserDescription "+ticket+" "+started.Format(time.RFC3339)+" "+timeSpent.String()+" "+description)
+	return nil
+}
+
+func (mock *MockJiraAPI) AddWorklog(ticket string, _ time.Time, timeSpent time.Duration, description string, options api.WorklogOptions) (string, error) {
+	mock.Calls = append(mock.Calls, "AddWorklog "+ticket+" "+timeSpent.String()+" "+description+visibleTo(options))
+	return "", nil
+}
+
+func (mock *MockJiraAPI) UpdateWorklog(ticket string, id string, timeSpent time.Duration, description string, options api.WorklogOptions) error {
+	mock.Calls = append(mock.Calls, "UpdateWorklog "+ticket+" "+id+" "+timeSpent.String()+" "+description+visibleTo(options))
+	return nil
+}
+
+func visibleTo(options api.WorklogOptions) string {
+	if options.Visibility == nil {
+		return ""
+	}
+	return " (" + options.Visibility.Type + " " + options.Visibility.Value + ")"
+}
+
+func (mock *MockJiraAPI) DeleteWorklog(ticket string, id string) error {
+	mock.Calls = append(mock.Calls, "DeleteWorklog "+ticket+" "+id)
+	return nil
+}
+
+func (mock *MockJiraAPI) GetMyself() (*api.JiraUser, error) {
+	return &api.JiraUser{}, nil
+}
+
+func (mock *MockJiraAPI) GetWorklogs(time.Time) ([]api.JiraWorklog, error) {
+	return nil, nil
+}
+
+func (mock *MockJiraAPI) ProjectExists(string) (bool, error) {
+	return true, nil
+}
+
+func (mock *MockJiraAPI) TicketExists(string) (bool, error) {
+	return true, nil
+}
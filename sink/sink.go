@@ -0,0 +1,41 @@
+package sink
+
+import "time"
+
+// Names of the available sinks, as referenced from config (see config.SyncSinks)
+const (
+	Jira  string = "jira"
+	CSV   string = "csv"
+	JSON  string = "json"
+	Tempo string = "tempo"
+)
+
+// WorklogSink is the contract for any target able to record the work done on a ticket.
+type WorklogSink interface {
+	Write(worklog Worklog) error
+}
+
+// WorklogUpdater is implemented by sinks able to update the worklogs they recorded (e.g. the partial worklogs of running entries).
+type WorklogUpdater interface {
+	Add(worklog Worklog) (id string, err error)
+	Update(id string, worklog Worklog) error
+}
+
+// Worklog contains the details of the work to be recorded against a ticket.
+// Started is when the work started, in the user's time zone (zero if unknown).
+// Partial worklogs include the time of running entries up to now, and are updated once those are stopped.
+// Comment is the text worklogs are written with, if set (sinks describe them with Description otherwise).
+// Billable tells whether the time entry (or any of the entries merged into it) was billable.
+type Worklog struct {
+	Date        string
+	Started     time.Time
+	Ticket      string
+	TimeSpent   time.Duration
+	Description string
+	Comment     string
+	Project     string
+	ProjectID   int
+	Overhead    bool
+	Partial     bool
+	Billable    bool
+}
@@ -0,0 +1,70 @@
+package sink
+
+import (
+	"strings"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+)
+
+// TempoSink is an implementation of WorklogSink that logs work on Tempo Timesheets.
+type TempoSink struct {
+	tempoAPI api.TempoAPI
+}
+
+// NewTempoSink creates a new sink logging work through the provided Tempo API client.
+func NewTempoSink(tempoAPI api.TempoAPI) WorklogSink {
+	return &TempoSink{tempoAPI: tempoAPI}
+}
+
+// Write logs the work on Tempo, including the work attributes mapped in config.
+// Overhead work uses the mapping of its Toggl project (if any), falling back to the mapping of the ticket's project key.
+// Work is billable if its time entry was, unless the mapping says otherwise.
+func (tempo *TempoSink) Write(worklog Worklog) error {
+	tempoWorklog := api.TempoWorklog{
+		Ticket:    worklog.Ticket,
+		TimeSpent: worklog.TimeSpent,
+		Date:      worklog.Date,
+		Started:   worklog.Started,
+		Billable:  worklog.Billable,
+	}
+	if worklog.Comment != "" {
+		tempoWorklog.Description = worklog.Comment
+	} else if worklog.Overhead {
+		tempoWorklog.Description = worklog.Description
+	}
+
+	if mapping := findTempoMapping(worklog); mapping != "" {
+		tempoWorklog.Account = config.Get(config.TempoMappingKey(mapping, config.TempoMappingAccount))
+		if config.IsSet(config.TempoMappingKey(mapping, config.TempoMappingBillable)) {
+			tempoWorklog.Billable = config.GetBool(config.TempoMappingKey(mapping, config.TempoMappingBillable))
+		}
+		tempoWorklog.Attributes = parseTempoAttributes(config.GetSlice(config.TempoMappingKey(mapping, config.TempoMappingAttributes)))
+	}
+
+	return tempo.tempoAPI.LogWork(tempoWorklog)
+}
+
+func findTempoMapping(worklog Worklog) string {
+	candidates := []string{strings.SplitN(worklog.Ticket, "-", 2)[0]}
+	if worklog.Overhead {
+		candidates = append([]string{worklog.Project}, candidates...)
+	}
+
+	for _, candidate := range candidates {
+		if candidate != "" && config.HasTempoMapping(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func parseTempoAttributes(attributes []string) map[string]string {
+	parsed := make(map[string]string)
+	for _, attribute := range attributes {
+		if key, value, ok := strings.Cut(attribute, "="); ok {
+			parsed[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return parsed
+}
@@ -0,0 +1,110 @@
+package sink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTempoSink_ProjectKeyMapping(t *testing.T) {
+	config.Reset()
+	config.Set(config.TempoMappingKey("ENG", config.TempoMappingAccount), "CLIENT-A")
+	config.Set(config.TempoMappingKey("ENG", config.TempoMappingAttributes), []string{"_Role_=Developer", "malformed"})
+	tempoAPI := &MockTempoAPI{}
+
+	started := time.Date(2020, 5, 22, 14, 30, 0, 0, time.UTC)
+
+	err := NewTempoSink(tempoAPI).Write(Worklog{
+		Date:        "2020-05-22",
+		Started:     started,
+		Ticket:      "ENG-1001",
+		TimeSpent:   time.Duration(60) * time.Second,
+		Description: "ENG-1001",
+		Billable:    true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []api.TempoWorklog{{
+		Ticket:     "ENG-1001",
+		TimeSpent:  time.Duration(60) * time.Second,
+		Date:       "2020-05-22",
+		Started:    started,
+		Account:    "CLIENT-A",
+		Billable:   true,
+		Attributes: map[string]string{"_Role_": "Developer"},
+	}}, tempoAPI.Worklogs)
+}
+
+func TestTempoSink_OverheadProjectMapping(t *testing.T) {
+	config.Reset()
+	config.Set(config.TempoMappingKey("MGMT", config.TempoMappingAccount), "MANAGEMENT")
+	config.Set(config.TempoMappingKey("Meetings", config.TempoMappingAccount), "INTERNAL")
+	config.Set(config.TempoMappingKey("Meetings", config.TempoMappingBillable), false)
+	tempoAPI := &MockTempoAPI{}
+
+	err := NewTempoSink(tempoAPI).Write(Worklog{
+		Date:        "2020-05-22",
+		Ticket:      "MGMT-1",
+		TimeSpent:   time.Duration(60) * time.Second,
+		Description: "Team catch-up",
+		Project:     "Meetings",
+		Overhead:    true,
+		Billable:    true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []api.TempoWorklog{{
+		Ticket:      "MGMT-1",
+		TimeSpent:   time.Duration(60) * time.Second,
+		Date:        "2020-05-22",
+		Description: "Team catch-up",
+		Account:     "INTERNAL",
+		Billable:    false,
+		Attributes:  map[string]string{},
+	}}, tempoAPI.Worklogs)
+}
+
+func TestTempoSink_NoMapping(t *testing.T) {
+	config.Reset()
+	tempoAPI := &MockTempoAPI{}
+
+	err := NewTempoSink(tempoAPI).Write(Worklog{
+		Date:      "2020-05-22",
+		Ticket:    "ENG-1001",
+		TimeSpent: time.Duration(60) * time.Second,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []api.TempoWorklog{{
+		Ticket:    "ENG-1001",
+		TimeSpent: time.Duration(60) * time.Second,
+		Date:      "2020-05-22",
+	}}, tempoAPI.Worklogs)
+}
+
+func TestTempoSink_BillableMapping(t *testing.T) {
+	config.Reset()
+	config.Set(config.TempoMappingKey("ENG", config.TempoMappingBillable), true)
+	tempoAPI := &MockTempoAPI{}
+
+	err := NewTempoSink(tempoAPI).Write(Worklog{
+		Date:      "2020-05-22",
+		Ticket:    "ENG-1001",
+		TimeSpent: time.Duration(60) * time.Second,
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, tempoAPI.Worklogs[0].Billable)
+}
+
+type MockTempoAPI struct {
+	Worklogs []api.TempoWorklog
+}
+
+func (mock *MockTempoAPI) LogWork(worklog api.TempoWorklog) error {
+	mock.Worklogs = append(mock.Worklogs, worklog)
+	return nil
+}
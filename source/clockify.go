@@ -0,0 +1,80 @@
+package source
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+)
+
+// ClockifySource is an implementation of TimeSource that retrieves time entries from Clockify.
+type ClockifySource struct {
+	clockifyAPI api.ClockifyAPI
+	projectIDs  map[int]string
+}
+
+// NewClockifySource creates a new source retrieving time entries through the provided Clockify API client.
+func NewClockifySource(clockifyAPI api.ClockifyAPI) TimeSource {
+	return &ClockifySource{
+		clockifyAPI: clockifyAPI,
+		projectIDs:  make(map[int]string),
+	}
+}
+
+// GetMe retrieves the Clockify user profile
+func (clockify *ClockifySource) GetMe() (*api.Me, error) {
+	user, err := clockify.clockifyAPI.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	return &api.Me{Data: api.PersonalInfo{Email: user.Email, Fullname: user.Name}}, nil
+}
+
+// GetTimeEntries retrieves all time entries of the current Clockify user within the given time period.
+// Clockify ids are converted into stable numeric ids, and running entries get a negative duration (like in Toggl).
+func (clockify *ClockifySource) GetTimeEntries(start time.Time, end time.Time) ([]api.TimeEntry, error) {
+	user, err := clockify.clockifyAPI.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	clockifyEntries, err := clockify.clockifyAPI.GetTimeEntries(user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]api.TimeEntry, 0, len(clockifyEntries))
+	for _, clockifyEntry := range clockifyEntries {
+		entry := api.TimeEntry{
+			Id:          stableID(clockifyEntry.ID),
+			Start:       clockifyEntry.TimeInterval.Start,
+			Description: clockifyEntry.Description,
+		}
+		if clockifyEntry.ProjectID != "" {
+			entry.Pid = stableID(clockifyEntry.ProjectID)
+			clockify.projectIDs[entry.Pid] = clockifyEntry.ProjectID
+		}
+		if clockifyEntry.TimeInterval.End != nil {
+			entry.Stop = *clockifyEntry.TimeInterval.End
+			entry.Duration = int(entry.Stop.Sub(entry.Start).Seconds())
+		} else {
+			entry.Duration = runningDuration(entry.Start)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetProjectById retrieves the Clockify project behind a numeric id returned as part of a time entry
+func (clockify *ClockifySource) GetProjectById(id int) (*api.Project, error) {
+	clockifyID, ok := clockify.projectIDs[id]
+	if !ok {
+		return nil, fmt.Errorf("[GetProjectById] Unknown project id: %d", id)
+	}
+
+	project, err := clockify.clockifyAPI.GetProjectById(clockifyID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Project{Data: api.ProjectData{Id: id, Name: project.Name}}, nil
+}
@@ -0,0 +1,87 @@
+package source
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockifySource_GetMe(t *testing.T) {
+	clockify := NewClockifySource(&MockClockifyAPI{})
+
+	me, err := clockify.(UserDetailsProvider).GetMe()
+	assert.Nil(t, err)
+	assert.Equal(t, api.PersonalInfo{Email: "tester@toggl-sync.com", Fullname: "TogglSync Tester"}, me.Data)
+}
+
+func TestClockifySource_GetTimeEntries(t *testing.T) {
+	start, _ := time.Parse(time.RFC3339, "2020-05-22T09:00:00Z")
+	end, _ := time.Parse(time.RFC3339, "2020-05-22T10:00:00Z")
+	clockify := NewClockifySource(&MockClockifyAPI{
+		Entries: []api.ClockifyTimeEntry{
+			{
+				ID:           "entry-1",
+				Description:  "Team catch-up",
+				ProjectID:    "project-1",
+				TimeInterval: api.ClockifyTimeInterval{Start: start, End: &end},
+			},
+			{
+				ID:           "entry-2",
+				Description:  "ENG-1001",
+				TimeInterval: api.ClockifyTimeInterval{Start: end},
+			},
+		},
+	})
+
+	entries, err := clockify.GetTimeEntries(start, end)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, api.TimeEntry{
+		Id:          stableID("entry-1"),
+		Pid:         stableID("project-1"),
+		Start:       start,
+		Stop:        end,
+		Duration:    3600,
+		Description: "Team catch-up",
+	}, entries[0])
+	assert.Equal(t, 0, entries[1].Pid)
+	assert.Less(t, entries[1].Duration, 0, "Running entries should have a negative duration")
+
+	project, err := clockify.GetProjectById(entries[0].Pid)
+	assert.Nil(t, err)
+	assert.Equal(t, api.ProjectData{Id: entries[0].Pid, Name: "Meetings"}, project.Data)
+}
+
+func TestClockifySource_GetTimeEntries_ErrorFetchingUser(t *testing.T) {
+	clockify := NewClockifySource(&MockClockifyAPI{UserError: errors.New("stub error")})
+
+	_, err := clockify.GetTimeEntries(time.Now(), time.Now())
+	assert.NotNil(t, err)
+}
+
+func TestClockifySource_GetProjectById_UnknownProject(t *testing.T) {
+	clockify := NewClockifySource(&MockClockifyAPI{})
+
+	_, err := clockify.GetProjectById(10)
+	assert.NotNil(t, err)
+}
+
+type MockClockifyAPI struct {
+	Entries   []api.ClockifyTimeEntry
+	UserError error
+}
+
+func (mock *MockClockifyAPI) GetCurrentUser() (*api.ClockifyUser, error) {
+	return &api.ClockifyUser{ID: "user-1", Name: "TogglSync Tester", Email: "tester@toggl-sync.com"}, mock.UserError
+}
+
+func (mock *MockClockifyAPI) GetTimeEntries(string, time.Time, time.Time) ([]api.ClockifyTimeEntry, error) {
+	return mock.Entries, nil
+}
+
+func (mock *MockClockifyAPI) GetProjectById(id string) (*api.ClockifyProject, error) {
+	return &api.ClockifyProject{ID: id, Name: "Meetings"}, nil
+}
@@ -0,0 +1,107 @@
+package source
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+)
+
+// CSVSource is an implementation of TimeSource that imports time entries from a CSV file.
+// The file must have a header row with (at least) "start", "description" and either "stop" or "duration" (seconds).
+// Optional columns are "project" (project name) and "tags" (separated by semicolons).
+type CSVSource struct {
+	projects namedProjects
+}
+
+// NewCSVSource creates a new source reading the CSV file configured in config.CSVSourcePath.
+func NewCSVSource() TimeSource {
+	return &CSVSource{projects: make(namedProjects)}
+}
+
+// GetTimeEntries reads all time entries from the CSV file starting within the given time period
+func (source *CSVSource) GetTimeEntries(start time.Time, end time.Time) ([]api.TimeEntry, error) {
+	path := config.Get(config.CSVSourcePath)
+	if path == "" {
+		return nil, fmt.Errorf("[CSVSource] No file configured! Please, set [%s] in the configuration file", config.CSVSourcePath)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("[CSVSource] Opening file failed! Error: %s", err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("[CSVSource] Reading file failed! Error: %s", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	var entries []api.TimeEntry
+	for line, record := range records[1:] {
+		entry, err := source.parseRecord(columns, record)
+		if err != nil {
+			return nil, fmt.Errorf("[CSVSource] Invalid entry at line %d: %s", line+2, err)
+		}
+		if !entry.Start.Before(start) && entry.Start.Before(end) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (source *CSVSource) parseRecord(columns map[string]int, record []string) (entry api.TimeEntry, err error) {
+	value := func(column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	entry.Start, err = time.Parse(time.RFC3339, value("start"))
+	if err != nil {
+		return
+	}
+
+	if stop := value("stop"); stop != "" {
+		if entry.Stop, err = time.Parse(time.RFC3339, stop); err != nil {
+			return
+		}
+		entry.Duration = int(entry.Stop.Sub(entry.Start).Seconds())
+	} else if duration := value("duration"); duration != "" {
+		if entry.Duration, err = strconv.Atoi(duration); err != nil {
+			return
+		}
+		entry.Stop = entry.Start.Add(time.Duration(entry.Duration) * time.Second)
+	} else {
+		entry.Duration = runningDuration(entry.Start)
+	}
+
+	entry.Description = value("description")
+	entry.Pid = source.projects.idFor(value("project"))
+	entry.Id = stableID(value("start") + "|" + entry.Description)
+	if tags := value("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ";") {
+			entry.Tags = append(entry.Tags, strings.TrimSpace(tag))
+		}
+	}
+	return
+}
+
+// GetProjectById retrieves the name of a project referenced from the CSV file
+func (source *CSVSource) GetProjectById(id int) (*api.Project, error) {
+	return source.projects.GetProjectById(id)
+}
@@ -0,0 +1,54 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVSource_GetTimeEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+	assert.Nil(t, os.WriteFile(path, []byte(
+		"start,stop,duration,description,project,tags\n"+
+			"2020-05-22T09:00:00Z,2020-05-22T10:00:00Z,,ENG-1001,,\n"+
+			"2020-05-22T10:00:00Z,,900,Team catch-up,Meetings,team;weekly\n"+
+			"2020-05-23T10:00:00Z,,900,Next day,Meetings,\n"), 0600))
+	config.Set(config.CSVSourcePath, path)
+
+	csvSource := NewCSVSource()
+	start, _ := time.Parse("2006-01-02", "2020-05-22")
+	entries, err := csvSource.GetTimeEntries(start, start.AddDate(0, 0, 1))
+
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "ENG-1001", entries[0].Description)
+	assert.Equal(t, 3600, entries[0].Duration)
+	assert.Equal(t, 0, entries[0].Pid)
+	assert.Equal(t, "Team catch-up", entries[1].Description)
+	assert.Equal(t, 900, entries[1].Duration)
+	assert.Equal(t, []string{"team", "weekly"}, entries[1].Tags)
+
+	project, err := csvSource.GetProjectById(entries[1].Pid)
+	assert.Nil(t, err)
+	assert.Equal(t, "Meetings", project.Data.Name)
+}
+
+func TestCSVSource_GetTimeEntries_InvalidEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+	assert.Nil(t, os.WriteFile(path, []byte("start,description\nyesterday,ENG-1001\n"), 0600))
+	config.Set(config.CSVSourcePath, path)
+
+	_, err := NewCSVSource().GetTimeEntries(time.Now(), time.Now())
+	assert.NotNil(t, err)
+}
+
+func TestCSVSource_GetTimeEntries_ErrorWhenPathNotConfigured(t *testing.T) {
+	config.Set(config.CSVSourcePath, "")
+
+	_, err := NewCSVSource().GetTimeEntries(time.Now(), time.Now())
+	assert.NotNil(t, err)
+}
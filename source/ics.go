@@ -0,0 +1,195 @@
+package source
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/javicg/toggl-sync/config"
+)
+
+const defaultICSProject = "Meetings"
+
+// ICSSource is an implementation of TimeSource that imports calendar events from an iCalendar (.ics) file.
+// Every event becomes a time entry assigned to a single (overhead) project, configured in config.ICSSourceProject.
+// All-day, cancelled and recurring events (RRULE) are not supported and are ignored.
+type ICSSource struct {
+	projects namedProjects
+}
+
+// NewICSSource creates a new source reading the iCalendar file configured in config.ICSSourcePath.
+func NewICSSource() TimeSource {
+	return &ICSSource{projects: make(namedProjects)}
+}
+
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// GetTimeEntries reads all events from the iCalendar file starting within the given time period
+func (source *ICSSource) GetTimeEntries(start time.Time, end time.Time) ([]api.TimeEntry, error) {
+	path := config.Get(config.ICSSourcePath)
+	if path == "" {
+		return nil, fmt.Errorf("[ICSSource] No file configured! Please, set [%s] in the configuration file", config.ICSSourcePath)
+	}
+
+	lines, err := readUnfoldedLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("[ICSSource] Reading file failed! Error: %s", err)
+	}
+
+	project := config.Get(config.ICSSourceProject)
+	if project == "" {
+		project = defaultICSProject
+	}
+
+	var entries []api.TimeEntry
+	var event map[string]icsProperty
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = make(map[string]icsProperty)
+		case line == "END:VEVENT" && event != nil:
+			entry, ok, err := source.parseEvent(event, project)
+			if err != nil {
+				return nil, fmt.Errorf("[ICSSource] Invalid event [%s]: %s", event["SUMMARY"].value, err)
+			}
+			if ok && !entry.Start.Before(start) && entry.Start.Before(end) {
+				entries = append(entries, entry)
+			}
+			event = nil
+		case event != nil:
+			property := parseICSProperty(line)
+			event[property.name] = property
+		}
+	}
+	return entries, nil
+}
+
+func (source *ICSSource) parseEvent(event map[string]icsProperty, project string) (entry api.TimeEntry, ok bool, err error) {
+	dtStart, hasStart := event["DTSTART"]
+	_, isRecurring := event["RRULE"]
+	if !hasStart || dtStart.params["VALUE"] == "DATE" || isRecurring || event["STATUS"].value == "CANCELLED" {
+		return entry, false, nil
+	}
+
+	entry.Start, err = parseICSTime(dtStart)
+	if err != nil {
+		return
+	}
+
+	if dtEnd, hasEnd := event["DTEND"]; hasEnd {
+		if entry.Stop, err = parseICSTime(dtEnd); err != nil {
+			return
+		}
+	} else if duration, hasDuration := event["DURATION"]; hasDuration {
+		var d time.Duration
+		if d, err = parseISO8601Duration(duration.value); err != nil {
+			return
+		}
+		entry.Stop = entry.Start.Add(d)
+	} else {
+		entry.Stop = entry.Start
+	}
+
+	entry.Duration = int(entry.Stop.Sub(entry.Start).Seconds())
+	entry.Description = unescapeICSText(event["SUMMARY"].value)
+	entry.Pid = source.projects.idFor(project)
+	entry.Id = stableID(event["UID"].value + "|" + dtStart.value)
+	return entry, true, nil
+}
+
+// GetProjectById retrieves the name of the project assigned to calendar events
+func (source *ICSSource) GetProjectById(id int) (*api.Project, error) {
+	return source.projects.GetProjectById(id)
+}
+
+func readUnfoldedLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+		} else if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func parseICSProperty(line string) icsProperty {
+	nameAndParams, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(nameAndParams, ";")
+
+	property := icsProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  value,
+	}
+	for _, param := range parts[1:] {
+		if key, paramValue, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(key)] = strings.Trim(paramValue, `"`)
+		}
+	}
+	return property
+}
+
+func parseICSTime(property icsProperty) (time.Time, error) {
+	if strings.HasSuffix(property.value, "Z") {
+		return time.Parse("20060102T150405Z", property.value)
+	}
+
+	location := time.Local
+	if tzid := property.params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			location = tz
+		}
+	}
+	return time.ParseInLocation("20060102T150405", property.value, location)
+}
+
+func unescapeICSText(text string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(text)
+}
+
+// parseISO8601Duration parses durations like "PT1H30M" (weeks, days, hours, minutes and seconds are supported)
+func parseISO8601Duration(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	if rest == value || rest == "" {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+
+	var duration time.Duration
+	number, inTime := 0, false
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case c == 'T':
+			inTime = true
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+		case c == 'M' && !inTime:
+			return 0, fmt.Errorf("invalid duration (months are not supported): %s", value)
+		case units[c] != 0:
+			duration += time.Duration(number) * units[c]
+			number = 0
+		default:
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+	}
+	return duration, nil
+}
@@ -0,0 +1,92 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-1\r\n" +
+	"DTSTART:20200522T090000Z\r\n" +
+	"DTEND:20200522T093000Z\r\n" +
+	"SUMMARY:Team catch-up\\, weekly\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-2\r\n" +
+	"DTSTART;TZID=Europe/Madrid:20200522T140000\r\n" +
+	"DURATION:PT1H\r\n" +
+	"SUMMARY:Sprint planning with a very long title that gets \r\n" +
+	" folded\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-3\r\n" +
+	"DTSTART;VALUE=DATE:20200522\r\n" +
+	"SUMMARY:Company holiday\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-4\r\n" +
+	"DTSTART:20200522T110000Z\r\n" +
+	"DTEND:20200522T120000Z\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"SUMMARY:Cancelled meeting\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-5\r\n" +
+	"DTSTART:20200523T090000Z\r\n" +
+	"DTEND:20200523T093000Z\r\n" +
+	"SUMMARY:Next day\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestICSSource_GetTimeEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendar.ics")
+	assert.Nil(t, os.WriteFile(path, []byte(testCalendar), 0600))
+	config.Set(config.ICSSourcePath, path)
+	config.Set(config.ICSSourceProject, "")
+
+	icsSource := NewICSSource()
+	start, _ := time.Parse("2006-01-02", "2020-05-22")
+	entries, err := icsSource.GetTimeEntries(start, start.AddDate(0, 0, 1))
+
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Team catch-up, weekly", entries[0].Description)
+	assert.Equal(t, 1800, entries[0].Duration)
+	assert.Equal(t, "Sprint planning with a very long title that gets folded", entries[1].Description)
+	assert.Equal(t, 3600, entries[1].Duration)
+	assert.Equal(t, "2020-05-22T12:00:00Z", entries[1].Start.UTC().Format(time.RFC3339))
+
+	project, err := icsSource.GetProjectById(entries[0].Pid)
+	assert.Nil(t, err)
+	assert.Equal(t, "Meetings", project.Data.Name)
+}
+
+func TestICSSource_GetTimeEntries_CustomProject(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendar.ics")
+	assert.Nil(t, os.WriteFile(path, []byte(testCalendar), 0600))
+	config.Set(config.ICSSourcePath, path)
+	config.Set(config.ICSSourceProject, "Calendar")
+
+	icsSource := NewICSSource()
+	start, _ := time.Parse("2006-01-02", "2020-05-22")
+	entries, err := icsSource.GetTimeEntries(start, start.AddDate(0, 0, 1))
+	assert.Nil(t, err)
+
+	project, err := icsSource.GetProjectById(entries[0].Pid)
+	assert.Nil(t, err)
+	assert.Equal(t, "Calendar", project.Data.Name)
+}
+
+func TestICSSource_GetTimeEntries_ErrorWhenFileDoesNotExist(t *testing.T) {
+	config.Set(config.ICSSourcePath, filepath.Join(t.TempDir(), "missing.ics"))
+
+	_, err := NewICSSource().GetTimeEntries(time.Now(), time.Now())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,201 @@
+package source
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+)
+
+// Names of the available sources, as referenced from config (see config.SyncSources)
+const (
+	Toggl    string = "toggl"
+	Clockify string = "clockify"
+	CSV      string = "csv"
+	ICS      string = "ics"
+)
+
+// TimeSource is the contract for any provider of time entries (and the projects they belong to).
+// api.TogglAPI is the reference implementation.
+type TimeSource interface {
+	GetTimeEntries(start time.Time, end time.Time) ([]api.TimeEntry, error)
+	GetProjectById(id int) (*api.Project, error)
+}
+
+// UserDetailsProvider is implemented by sources able to describe the user they retrieve time entries for.
+type UserDetailsProvider interface {
+	GetMe() (*api.Me, error)
+}
+
+// TimerStopper is implemented by sources able to stop running time entries (e.g. Toggl timers).
+type TimerStopper interface {
+	StopTimeEntry(id int) (*api.TimeEntry, error)
+}
+
+// ClientProvider is implemented by sources able to list the clients projects are done for (e.g. Toggl clients).
+type ClientProvider interface {
+	GetClients() ([]api.ClientData, error)
+}
+
+// MultiSource is an implementation of TimeSource that merges the time entries of several sources.
+// It is also a ClientProvider and a TimerStopper, delegating on the sources able to do so.
+// Ids of entries and projects are mapped into a single space (see idSpace), so calls reach the source that provided them.
+type MultiSource struct {
+	sources  []TimeSource
+	entries  idSpace
+	projects idSpace
+}
+
+// NewMultiSource creates a new TimeSource combining the time entries of all the provided sources.
+func NewMultiSource(sources ...TimeSource) TimeSource {
+	return &MultiSource{
+		sources:  sources,
+		entries:  newIDSpace(),
+		projects: newIDSpace(),
+	}
+}
+
+// GetTimeEntries retrieves the time entries of every source within the given time period, in source order
+func (multi *MultiSource) GetTimeEntries(start time.Time, end time.Time) ([]api.TimeEntry, error) {
+	var entries []api.TimeEntry
+	for i, source := range multi.sources {
+		sourceEntries, err := source.GetTimeEntries(start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range sourceEntries {
+			entry.Id = multi.entries.idFor(i, entry.Id)
+			entry.Pid = multi.projects.idFor(i, entry.Pid)
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// GetProjectById retrieves the project data from the source that provided the project id
+func (multi *MultiSource) GetProjectById(id int) (*api.Project, error) {
+	origin, ok := multi.projects.origin(id)
+	if !ok {
+		return nil, fmt.Errorf("[GetProjectById] Unknown project id: %d", id)
+	}
+	project, err := multi.sources[origin.source].GetProjectById(origin.id)
+	if err != nil {
+		return nil, err
+	}
+	mapped := *project
+	mapped.Data.Id = id
+	return &mapped, nil
+}
+
+// GetClients retrieves the clients of every source able to list them, in source order
+func (multi *MultiSource) GetClients() ([]api.ClientData, error) {
+	var clients []api.ClientData
+	for _, source := range multi.sources {
+		provider, ok := source.(ClientProvider)
+		if !ok {
+			continue
+		}
+		sourceClients, err := provider.GetClients()
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, sourceClients...)
+	}
+	return clients, nil
+}
+
+// StopTimeEntry stops the running time entry through the source that provided it
+func (multi *MultiSource) StopTimeEntry(id int) (*api.TimeEntry, error) {
+	origin, ok := multi.entries.origin(id)
+	if !ok {
+		return nil, fmt.Errorf("[StopTimeEntry] Unknown time entry id: %d", id)
+	}
+	stopper, ok := multi.sources[origin.source].(TimerStopper)
+	if !ok {
+		return nil, fmt.Errorf("[StopTimeEntry] The source of time entry %d cannot stop running timers", id)
+	}
+	stopped, err := stopper.StopTimeEntry(origin.id)
+	if err != nil {
+		return nil, err
+	}
+	mapped := *stopped
+	mapped.Id = id
+	mapped.Pid = multi.projects.idFor(origin.source, stopped.Pid)
+	return &mapped, nil
+}
+
+// sourceID is an id (of a time entry or a project) as known by the source that provided it (its index in MultiSource)
+type sourceID struct {
+	source int
+	id     int
+}
+
+// idSpace maps the ids of several sources into a single space. Ids are kept as they are, unless another source already
+// provided the same one (e.g. a Toggl id matching an id hashed by a file source, see stableID): a stable id is derived then.
+type idSpace struct {
+	ids     map[sourceID]int
+	origins map[int]sourceID
+}
+
+func newIDSpace() idSpace {
+	return idSpace{ids: make(map[sourceID]int), origins: make(map[int]sourceID)}
+}
+
+// idFor returns the id in the space for the id provided by the source (0, i.e. no id, is kept as it is)
+func (space idSpace) idFor(source int, id int) int {
+	key := sourceID{source: source, id: id}
+	if id == 0 {
+		return 0
+	} else if mapped, ok := space.ids[key]; ok {
+		return mapped
+	}
+
+	mapped := id
+	for n := 1; ; n++ {
+		if _, taken := space.origins[mapped]; !taken && mapped != 0 {
+			break
+		}
+		mapped = stableID(fmt.Sprintf("%d|%d|%d", source, id, n))
+	}
+	space.ids[key] = mapped
+	space.origins[mapped] = key
+	return mapped
+}
+
+// origin returns the source (and the id known by it) of an id in the space
+func (space idSpace) origin(id int) (sourceID, bool) {
+	origin, ok := space.origins[id]
+	return origin, ok
+}
+
+// stableID generates a numeric id that remains the same across runs for the same input
+func stableID(value string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return int(h.Sum32() & 0x7fffffff)
+}
+
+// namedProjects keeps track of projects only known by their name (e.g. projects from files)
+type namedProjects map[int]string
+
+func (projects namedProjects) idFor(name string) int {
+	if name == "" {
+		return 0
+	}
+	id := stableID(name)
+	projects[id] = name
+	return id
+}
+
+func (projects namedProjects) GetProjectById(id int) (*api.Project, error) {
+	name, ok := projects[id]
+	if !ok {
+		return nil, fmt.Errorf("[GetProjectById] Unknown project id: %d", id)
+	}
+	return &api.Project{Data: api.ProjectData{Id: id, Name: name}}, nil
+}
+
+func runningDuration(start time.Time) int {
+	return int(-start.Unix())
+}
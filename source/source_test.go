@@ -0,0 +1,146 @@
+package source
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/javicg/toggl-sync/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiSource(t *testing.T) {
+	first := &MockSource{
+		Entries:  []api.TimeEntry{{Id: 1, Pid: 10, Description: "Writing toggl-sync tests"}},
+		Projects: map[int]string{10: "testing"},
+	}
+	second := &MockSource{
+		Entries:  []api.TimeEntry{{Id: 2, Pid: 20, Description: "Team catch-up"}, {Id: 3, Description: "ENG-1001"}},
+		Projects: map[int]string{20: "Meetings"},
+	}
+
+	multi := NewMultiSource(first, second)
+	entries, err := multi.GetTimeEntries(time.Now(), time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, append(first.Entries, second.Entries...), entries)
+
+	project, err := multi.GetProjectById(20)
+	assert.Nil(t, err)
+	assert.Equal(t, "Meetings", project.Data.Name)
+
+	_, err = multi.GetProjectById(30)
+	assert.NotNil(t, err)
+}
+
+func TestMultiSource_SameIdsInDifferentSources(t *testing.T) {
+	first := &MockSource{
+		Entries:  []api.TimeEntry{{Id: 1, Pid: 10, Description: "Writing toggl-sync tests"}},
+		Projects: map[int]string{10: "testing"},
+	}
+	second := &MockTimerSource{MockSource: MockSource{
+		Entries:  []api.TimeEntry{{Id: 1, Pid: 10, Duration: -1590000000, Description: "Team catch-up"}},
+		Projects: map[int]string{10: "Meetings"},
+	}}
+
+	multi := NewMultiSource(first, second)
+	entries, err := multi.GetTimeEntries(time.Now(), time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, entries[0].Id)
+	assert.Equal(t, 10, entries[0].Pid)
+	assert.NotEqual(t, 1, entries[1].Id)
+	assert.NotEqual(t, 10, entries[1].Pid)
+
+	project, err := multi.GetProjectById(entries[1].Pid)
+	assert.Nil(t, err)
+	assert.Equal(t, api.ProjectData{Id: entries[1].Pid, Name: "Meetings"}, project.Data)
+	project, err = multi.GetProjectById(10)
+	assert.Nil(t, err)
+	assert.Equal(t, "testing", project.Data.Name)
+
+	stopped, err := multi.(TimerStopper).StopTimeEntry(entries[1].Id)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, second.Stopped)
+	assert.Equal(t, entries[1].Id, stopped.Id)
+
+	// Ids are the same across calls
+	again, err := multi.GetTimeEntries(time.Now(), time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, entries, again)
+}
+
+func TestMultiSource_ErrorRetrievingEntries(t *testing.T) {
+	multi := NewMultiSource(&MockSource{}, &MockSource{Error: errors.New("stub error")})
+	_, err := multi.GetTimeEntries(time.Now(), time.Now())
+	assert.NotNil(t, err)
+}
+
+func TestMultiSource_Clients(t *testing.T) {
+	multi := NewMultiSource(&MockSource{}, &MockTimerSource{Clients: []api.ClientData{{Id: 3, Name: "ACME"}}})
+
+	clients, err := multi.(ClientProvider).GetClients()
+	assert.Nil(t, err)
+	assert.Equal(t, []api.ClientData{{Id: 3, Name: "ACME"}}, clients)
+}
+
+func TestMultiSource_StopTimeEntry(t *testing.T) {
+	first := &MockSource{Entries: []api.TimeEntry{{Id: 1, Description: "ENG-1001"}}}
+	second := &MockTimerSource{MockSource: MockSource{Entries: []api.TimeEntry{{Id: 2, Duration: -1590000000, Description: "ENG-1002"}}}}
+
+	multi := NewMultiSource(first, second)
+	_, err := multi.GetTimeEntries(time.Now(), time.Now())
+	assert.Nil(t, err)
+
+	stopped, err := multi.(TimerStopper).StopTimeEntry(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2}, second.Stopped)
+	assert.Equal(t, 2, stopped.Id)
+
+	_, err = multi.(TimerStopper).StopTimeEntry(1)
+	assert.EqualError(t, err, "[StopTimeEntry] The source of time entry 1 cannot stop running timers")
+	_, err = multi.(TimerStopper).StopTimeEntry(3)
+	assert.EqualError(t, err, "[StopTimeEntry] Unknown time entry id: 3")
+}
+
+func TestParseISO8601Duration(t *testing.T) {
+	duration, err := parseISO8601Duration("PT1H30M15S")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Hour+30*time.Minute+15*time.Second, duration)
+
+	duration, err = parseISO8601Duration("P1D")
+	assert.Nil(t, err)
+	assert.Equal(t, 24*time.Hour, duration)
+
+	_, err = parseISO8601Duration("1H")
+	assert.NotNil(t, err)
+	_, err = parseISO8601Duration("P1M")
+	assert.NotNil(t, err)
+}
+
+type MockSource struct {
+	Entries  []api.TimeEntry
+	Projects map[int]string
+	Error    error
+}
+
+func (mock *MockSource) GetTimeEntries(time.Time, time.Time) ([]api.TimeEntry, error) {
+	return mock.Entries, mock.Error
+}
+
+func (mock *MockSource) GetProjectById(id int) (*api.Project, error) {
+	return &api.Project{Data: api.ProjectData{Id: id, Name: mock.Projects[id]}}, nil
+}
+
+type MockTimerSource struct {
+	MockSource
+	Clients []api.ClientData
+	Stopped []int
+}
+
+func (mock *MockTimerSource) GetClients() ([]api.ClientData, error) {
+	return mock.Clients, nil
+}
+
+func (mock *MockTimerSource) StopTimeEntry(id int) (*api.TimeEntry, error) {
+	mock.Stopped = append(mock.Stopped, id)
+	return &api.TimeEntry{Id: id}, nil
+}